@@ -41,6 +41,91 @@ var QFieldModulus = [6]uint64{0xb9feffffffffaaab, 0x1eabfffeb153ffff, 0x6730d2a0
 
 const montInvFQ = uint64(0x89f3fffcfffcfffd)
 
+var RFieldModulus = [4]uint64{0xffffffff00000001, 0x53bda402fffe5bfe, 0x3339d80809a1d805, 0x73eda753299d7d48}
+
+const montInvFR = uint64(0xfffffffeffffffff)
+
+// saveFramePointer allocates an unused stack slot. A non-empty frame makes the
+// assembler save and restore BP, which the register allocator may hand out.
+func saveFramePointer() {
+	AllocLocal(8)
+}
+
+// reduceOnceFR subtracts RFieldModulus from t if t >= RFieldModulus. hi holds
+// any bits above the lowest four limbs.
+func reduceOnceFR(t []Register, hi Register) {
+	s := make([]Register, 4)
+	for i := range s {
+		s[i] = GP64()
+		MOVQ(t[i], s[i])
+	}
+	modulus := GP64()
+	Comment("s = t - RFieldModulus")
+	MOVQ(Imm(RFieldModulus[0]), modulus)
+	SUBQ(modulus, s[0])
+	for i := 1; i < 4; i++ {
+		MOVQ(Imm(RFieldModulus[i]), modulus)
+		SBBQ(modulus, s[i])
+	}
+	SBBQ(Imm(0), hi)
+	Comment("if no borrow occurred, t = s")
+	for i := range s {
+		CMOVQCC(s[i], t[i])
+	}
+}
+
+// montMulFR multiplies a and b using coarsely integrated operand scanning
+// Montgomery multiplication and returns the result.
+func montMulFR(a, b string) {
+	t := make([]Register, 6)
+	for i := 0; i < 5; i++ {
+		t[i] = GP64()
+		Commentf("t[%d] = 0", i)
+		XORQ(t[i], t[i])
+	}
+	t[5] = GP64()
+	carry := GP64()
+	bi := GP64()
+	m := GP64()
+
+	for i := 0; i < 4; i++ {
+		Load(Param(b).Index(i), bi)
+		Comment("carry = 0")
+		XORQ(carry, carry)
+		for j := 0; j < 4; j++ {
+			aj := Load(Param(a).Index(j), RAX)
+			Commentf("(carry, t[%d]) = t[%d] + %s[%d] * %s[%d] + carry", j, j, a, j, b, i)
+			muladdc(t[j], aj, bi, carry)
+		}
+		Comment("(t[5], t[4]) = t[4] + carry")
+		XORQ(t[5], t[5])
+		ADDQ(carry, t[4])
+		ADCQ(Imm(0), t[5])
+
+		Commentf("m = (t[0] * %d) & 0xFFFFFFFFFFFFFFFF", montInvFR)
+		MOVQ(Imm(montInvFR), m)
+		IMULQ(t[0], m)
+		Comment("carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF")
+		XORQ(carry, carry)
+		muladdcConst(t[0], Imm(RFieldModulus[0]), m, carry)
+		for j := 1; j < 4; j++ {
+			Commentf("(carry, t[%d]) = t[%d] + RFieldModulus[%d] * m + carry", j, j, j)
+			muladdcConst(t[j], Imm(RFieldModulus[j]), m, carry)
+		}
+		Comment("(t[5], t[4]) = t[5] + t[4] + carry")
+		ADDQ(carry, t[4])
+		ADCQ(Imm(0), t[5])
+
+		// t = t >> 64 by renaming the limb registers
+		t = append(t[1:], t[0])
+	}
+
+	reduceOnceFR(t[:4], t[4])
+	for i := 0; i < 4; i++ {
+		Store(t[i], ReturnIndex(0).Index(i))
+	}
+}
+
 func r(i int) Component {
 	if i >= 6 {
 		return Param("hi").Index(i % 6)
@@ -51,6 +136,7 @@ func r(i int) Component {
 
 func main() {
 	Package("github.com/phoreproject/bls")
+	ConstraintExpr("!purego")
 	Implement("MACWithCarry")
 	Doc("Finds a + b * c + carry and returns the result and the carry.")
 	b := Load(Param("b"), GP64())
@@ -291,5 +377,75 @@ func main() {
 	}
 
 	RET()
+
+	Implement("MultiplyFR")
+	saveFramePointer()
+	montMulFR("a", "b")
+	RET()
+
+	Implement("SquareFR")
+	saveFramePointer()
+	montMulFR("a", "a")
+	RET()
+
+	Implement("AddFR")
+	saveFramePointer()
+	aRegs = make([]Register, 4)
+	bRegs = make([]Register, 4)
+	for i := range aRegs {
+		aRegs[i] = Load(Param("a").Index(i), GP64())
+		bRegs[i] = Load(Param("b").Index(i), GP64())
+	}
+
+	Comment("a = a + b")
+	ADDQ(bRegs[0], aRegs[0])
+	for i := 1; i < 4; i++ {
+		ADCQ(bRegs[i], aRegs[i])
+	}
+
+	// a + b < 2^256 because the modulus is less than 2^255.
+	hi := GP64()
+	XORQ(hi, hi)
+	reduceOnceFR(aRegs, hi)
+	for i := range aRegs {
+		Store(aRegs[i], ReturnIndex(0).Index(i))
+	}
+	RET()
+
+	Implement("SubFR")
+	saveFramePointer()
+	aRegs = make([]Register, 4)
+	bRegs = make([]Register, 4)
+	for i := range aRegs {
+		aRegs[i] = Load(Param("a").Index(i), GP64())
+		bRegs[i] = Load(Param("b").Index(i), GP64())
+	}
+
+	mask := GP64()
+	MOVQ(U32(0), mask)
+	Comment("a = a - b")
+	SUBQ(bRegs[0], aRegs[0])
+	for i := 1; i < 4; i++ {
+		SBBQ(bRegs[i], aRegs[i])
+	}
+	Comment("mask = 0xFFFFFFFFFFFFFFFF if a borrow occurred, otherwise 0")
+	SBBQ(mask, mask)
+
+	modulus := make([]Register, 4)
+	for i := range modulus {
+		modulus[i] = GP64()
+		MOVQ(Imm(RFieldModulus[i]), modulus[i])
+		ANDQ(mask, modulus[i])
+	}
+	Comment("a = a + (RFieldModulus & mask)")
+	ADDQ(modulus[0], aRegs[0])
+	for i := 1; i < 4; i++ {
+		ADCQ(modulus[i], aRegs[i])
+	}
+	for i := range aRegs {
+		Store(aRegs[i], ReturnIndex(0).Index(i))
+	}
+	RET()
+
 	Generate()
 }
@@ -0,0 +1,252 @@
+package bls
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// No object identifiers have been registered for BLS12-381 keys. The
+// default identifiers of g1pubs and g2pubs are under the arc
+// 2.25.209824747550876313493300493992439978690, which is derived from a
+// UUID as described in ITU-T X.667 and so is owned by this project
+// without registration. Applications that interoperate with another
+// assignment pass it to the WithOID functions of those packages.
+
+// An OID is an object identifier. Unlike asn1.ObjectIdentifier, it can
+// hold arcs that do not fit in an int, such as those of the UUID-based
+// identifiers under 2.25. The zero OID is not valid.
+type OID struct {
+	// der holds the content octets of the DER encoding.
+	der string
+}
+
+// ParseOID parses an object identifier in dotted decimal notation.
+func ParseOID(s string) (OID, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) < 2 {
+		return OID{}, fmt.Errorf("%w: object identifier %q has fewer than two arcs", ErrInvalidEncoding, s)
+	}
+	arcs := make([]*big.Int, len(parts))
+	for i, part := range parts {
+		arc, ok := new(big.Int).SetString(part, 10)
+		if !ok || arc.Sign() < 0 || part[0] == '+' || (len(part) > 1 && part[0] == '0') {
+			return OID{}, fmt.Errorf("%w: invalid arc %q in object identifier %q", ErrInvalidEncoding, part, s)
+		}
+		arcs[i] = arc
+	}
+	if arcs[0].Cmp(big.NewInt(2)) > 0 || (arcs[0].Cmp(big.NewInt(2)) < 0 && arcs[1].Cmp(big.NewInt(40)) >= 0) {
+		return OID{}, fmt.Errorf("%w: invalid object identifier %q", ErrInvalidEncoding, s)
+	}
+
+	first := new(big.Int).Mul(arcs[0], big.NewInt(40))
+	first.Add(first, arcs[1])
+	var der []byte
+	for _, arc := range append([]*big.Int{first}, arcs[2:]...) {
+		der = appendBase128(der, arc)
+	}
+	return OID{der: string(der)}, nil
+}
+
+// NewOID converts an asn1.ObjectIdentifier to an OID.
+func NewOID(oid asn1.ObjectIdentifier) (OID, error) {
+	der, err := asn1.Marshal(oid)
+	if err != nil {
+		return OID{}, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return OID{}, err
+	}
+	return OID{der: string(raw.Bytes)}, nil
+}
+
+func appendBase128(b []byte, n *big.Int) []byte {
+	var digits []byte
+	n = new(big.Int).Set(n)
+	for {
+		digits = append(digits, byte(n.Uint64()&0x7f))
+		n.Rsh(n, 7)
+		if n.Sign() == 0 {
+			break
+		}
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if i != 0 {
+			d |= 0x80
+		}
+		b = append(b, d)
+	}
+	return b
+}
+
+// decodeOID decodes the content octets of an object identifier.
+func decodeOID(der []byte) ([]*big.Int, error) {
+	if len(der) == 0 || der[len(der)-1]&0x80 != 0 {
+		return nil, fmt.Errorf("%w: malformed object identifier", ErrInvalidEncoding)
+	}
+	var arcs []*big.Int
+	n := new(big.Int)
+	start := true
+	for _, b := range der {
+		if start && b == 0x80 {
+			return nil, fmt.Errorf("%w: object identifier is not minimally encoded", ErrInvalidEncoding)
+		}
+		n.Lsh(n, 7)
+		n.Or(n, big.NewInt(int64(b&0x7f)))
+		start = b&0x80 == 0
+		if start {
+			arcs = append(arcs, n)
+			n = new(big.Int)
+		}
+	}
+
+	first := arcs[0]
+	switch {
+	case first.Cmp(big.NewInt(40)) < 0:
+		arcs = append([]*big.Int{big.NewInt(0)}, arcs...)
+	case first.Cmp(big.NewInt(80)) < 0:
+		arcs = append([]*big.Int{big.NewInt(1), new(big.Int).Sub(first, big.NewInt(40))}, arcs[1:]...)
+	default:
+		arcs = append([]*big.Int{big.NewInt(2), new(big.Int).Sub(first, big.NewInt(80))}, arcs[1:]...)
+	}
+	return arcs, nil
+}
+
+// String returns the object identifier in dotted decimal notation.
+func (o OID) String() string {
+	arcs, err := decodeOID([]byte(o.der))
+	if err != nil {
+		return "<invalid>"
+	}
+	parts := make([]string, len(arcs))
+	for i, arc := range arcs {
+		parts[i] = arc.String()
+	}
+	return strings.Join(parts, ".")
+}
+
+// Equal reports whether two object identifiers are the same.
+func (o OID) Equal(other OID) bool {
+	return o.der == other.der
+}
+
+func (o OID) algorithm() (algorithmIdentifier, error) {
+	if o.der == "" {
+		return algorithmIdentifier{}, fmt.Errorf("%w: zero object identifier", ErrInvalidEncoding)
+	}
+	return algorithmIdentifier{Algorithm: asn1.RawValue{Tag: asn1.TagOID, Bytes: []byte(o.der)}}, nil
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.RawValue
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type privateKeyInfo struct {
+	Version    int
+	Algorithm  algorithmIdentifier
+	PrivateKey []byte
+}
+
+func unmarshalDER(der []byte, out interface{}) error {
+	rest, err := asn1.Unmarshal(der, out)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	if len(rest) != 0 {
+		return fmt.Errorf("%w: trailing data after DER", ErrInvalidEncoding)
+	}
+	return nil
+}
+
+func checkAlgorithm(a algorithmIdentifier, oid OID) error {
+	if a.Algorithm.Class != asn1.ClassUniversal || a.Algorithm.Tag != asn1.TagOID || a.Algorithm.IsCompound {
+		return fmt.Errorf("%w: algorithm is not an object identifier", ErrInvalidEncoding)
+	}
+	if _, err := decodeOID(a.Algorithm.Bytes); err != nil {
+		return err
+	}
+	got := OID{der: string(a.Algorithm.Bytes)}
+	if !got.Equal(oid) {
+		return fmt.Errorf("%w: %v", ErrUnexpectedAlgorithm, got)
+	}
+	if len(a.Parameters.FullBytes) != 0 {
+		return fmt.Errorf("%w: unexpected algorithm parameters", ErrInvalidEncoding)
+	}
+	return nil
+}
+
+// MarshalSPKI encodes a public key as a DER SubjectPublicKeyInfo with the
+// given algorithm and no parameters.
+func MarshalSPKI(oid OID, publicKey []byte) ([]byte, error) {
+	algorithm, err := oid.algorithm()
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithm,
+		PublicKey: asn1.BitString{Bytes: publicKey, BitLength: 8 * len(publicKey)},
+	})
+}
+
+// ParseSPKI decodes a DER SubjectPublicKeyInfo and returns the public key
+// bytes if the algorithm matches.
+func ParseSPKI(der []byte, oid OID) ([]byte, error) {
+	var spki subjectPublicKeyInfo
+	if err := unmarshalDER(der, &spki); err != nil {
+		return nil, err
+	}
+	if err := checkAlgorithm(spki.Algorithm, oid); err != nil {
+		return nil, err
+	}
+	if spki.PublicKey.BitLength%8 != 0 {
+		return nil, fmt.Errorf("%w: public key is not a whole number of bytes", ErrInvalidEncoding)
+	}
+	return spki.PublicKey.Bytes, nil
+}
+
+// MarshalPKCS8 encodes a secret key as a DER PKCS #8 PrivateKeyInfo with
+// the given algorithm. As in RFC 8410, the private key field holds the
+// key wrapped in an OCTET STRING.
+func MarshalPKCS8(oid OID, secretKey []byte) ([]byte, error) {
+	algorithm, err := oid.algorithm()
+	if err != nil {
+		return nil, err
+	}
+	inner, err := asn1.Marshal(secretKey)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(privateKeyInfo{
+		Algorithm:  algorithm,
+		PrivateKey: inner,
+	})
+}
+
+// ParsePKCS8 decodes a DER PKCS #8 PrivateKeyInfo and returns the secret
+// key bytes if the algorithm matches.
+func ParsePKCS8(der []byte, oid OID) ([]byte, error) {
+	var info privateKeyInfo
+	if err := unmarshalDER(der, &info); err != nil {
+		return nil, err
+	}
+	if info.Version != 0 {
+		return nil, fmt.Errorf("%w: unsupported PKCS #8 version %d", ErrInvalidEncoding, info.Version)
+	}
+	if err := checkAlgorithm(info.Algorithm, oid); err != nil {
+		return nil, err
+	}
+	var secretKey []byte
+	if err := unmarshalDER(info.PrivateKey, &secretKey); err != nil {
+		return nil, err
+	}
+	return secretKey, nil
+}
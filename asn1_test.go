@@ -0,0 +1,137 @@
+package bls_test
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+// Arbitrary object identifiers for the tests.
+var (
+	testASN1OIDA = asn1.ObjectIdentifier{1, 2, 3, 4, 1}
+	testASN1OIDB = asn1.ObjectIdentifier{1, 2, 3, 4, 2}
+	testOIDA, _  = bls.NewOID(testASN1OIDA)
+	testOIDB, _  = bls.NewOID(testASN1OIDB)
+)
+
+func TestOID(t *testing.T) {
+	const uuidOID = "2.25.209824747550876313493300493992439978690.1"
+	oid, err := bls.ParseOID(uuidOID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oid.String() != uuidOID {
+		t.Fatalf("unexpected string %s", oid)
+	}
+	// Encoded with openssl asn1parse -genstr OID:...
+	expected, _ := hex.DecodeString("301c301706156982bbdae3b194eef28cd9a9b2efebe5ade8b54201030100")
+	der, err := bls.MarshalSPKI(oid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(der, expected) {
+		t.Fatalf("unexpected encoding %x", der)
+	}
+	if _, err := bls.ParseSPKI(der, oid); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bls.ParseSPKI(der, testOIDA); !errors.Is(err, bls.ErrUnexpectedAlgorithm) || !strings.Contains(err.Error(), uuidOID) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm naming %s, got %v", uuidOID, err)
+	}
+
+	parsed, err := bls.ParseOID("1.2.3.4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(testOIDA) || parsed.Equal(testOIDB) || testOIDA.String() != "1.2.3.4.1" {
+		t.Fatal("object identifiers from ParseOID and NewOID differ")
+	}
+
+	for _, s := range []string{"", "1", "3.1", "1.40", "1.a", "1.+2", "1.02", "1..2", "1.-2"} {
+		if _, err := bls.ParseOID(s); !errors.Is(err, bls.ErrInvalidEncoding) {
+			t.Fatalf("expected ErrInvalidEncoding for %q, got %v", s, err)
+		}
+	}
+	if _, err := bls.MarshalSPKI(bls.OID{}, nil); err == nil {
+		t.Fatal("the zero object identifier should not be encoded")
+	}
+}
+
+func TestSPKIRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0xab}, 48)
+	der, err := bls.MarshalSPKI(testOIDA, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := bls.ParseSPKI(der, testOIDA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, key) {
+		t.Fatal("public key did not round trip")
+	}
+
+	if _, err := bls.ParseSPKI(der, testOIDB); !errors.Is(err, bls.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+	if _, err := bls.ParseSPKI(append(der, 0), testOIDA); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for trailing data, got %v", err)
+	}
+	if _, err := bls.ParseSPKI(der[:len(der)-1], testOIDA); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for truncated data, got %v", err)
+	}
+}
+
+func TestSPKIRejectsParameters(t *testing.T) {
+	type algorithmIdentifier struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue
+	}
+	der, _ := asn1.Marshal(struct {
+		Algorithm algorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: algorithmIdentifier{testASN1OIDA, asn1.NullRawValue},
+		PublicKey: asn1.BitString{Bytes: make([]byte, 48), BitLength: 384},
+	})
+	if _, err := bls.ParseSPKI(der, testOIDA); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for algorithm parameters, got %v", err)
+	}
+}
+
+func TestPKCS8RoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, 32)
+	der, err := bls.MarshalPKCS8(testOIDB, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := bls.ParsePKCS8(der, testOIDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, key) {
+		t.Fatal("secret key did not round trip")
+	}
+
+	if _, err := bls.ParsePKCS8(der, testOIDA); !errors.Is(err, bls.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+
+	type algorithmIdentifier struct {
+		Algorithm asn1.ObjectIdentifier
+	}
+	inner, _ := asn1.Marshal(key)
+	v1, _ := asn1.Marshal(struct {
+		Version    int
+		Algorithm  algorithmIdentifier
+		PrivateKey []byte
+	}{1, algorithmIdentifier{testASN1OIDB}, inner})
+	if _, err := bls.ParsePKCS8(v1, testOIDB); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for version 1, got %v", err)
+	}
+}
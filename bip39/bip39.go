@@ -0,0 +1,209 @@
+// Package bip39 implements BIP-39 mnemonics with the English wordlist and
+// derives EIP-2333 BLS secret keys from them.
+package bip39
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/phoreproject/bls/eip2333"
+	"github.com/phoreproject/bls/g1pubs"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MnemonicEntropyBits is the entropy of the 24-word mnemonics created by
+// GenerateMnemonic.
+const MnemonicEntropyBits = 256
+
+const (
+	bitsPerWord     = 11
+	seedIterations  = 2048
+	seedLength      = 64
+	seedSaltPrefix  = "mnemonic"
+	minEntropyBits  = 128
+	maxEntropyBits  = 256
+	entropyBitsStep = 32
+)
+
+var (
+	// ErrInvalidEntropyLength is returned when entropy is not between 128
+	// and 256 bits in multiples of 32.
+	ErrInvalidEntropyLength = errors.New("entropy must be 128 to 256 bits in multiples of 32")
+
+	// ErrInvalidMnemonic is returned when a mnemonic has the wrong number
+	// of words or words that are not in the wordlist. More specific
+	// mnemonic errors wrap it.
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+	// ErrInvalidChecksum is returned when the checksum of a mnemonic does
+	// not match its entropy.
+	ErrInvalidChecksum = fmt.Errorf("%w: checksum does not match", ErrInvalidMnemonic)
+)
+
+var wordIndex = func() map[string]int {
+	m := make(map[string]int, len(english))
+	for i, w := range english {
+		m[w] = i
+	}
+	return m
+}()
+
+func checkEntropyBits(bits int) error {
+	if bits < minEntropyBits || bits > maxEntropyBits || bits%entropyBitsStep != 0 {
+		return ErrInvalidEntropyLength
+	}
+	return nil
+}
+
+// NewEntropy reads entropy of the given number of bits from r.
+func NewEntropy(r io.Reader, bits int) ([]byte, error) {
+	if err := checkEntropyBits(bits); err != nil {
+		return nil, err
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := io.ReadFull(r, entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+func getBit(b []byte, i int) int {
+	return int(b[i/8]>>(7-uint(i%8))) & 1
+}
+
+func setBit(b []byte, i int) {
+	b[i/8] |= 1 << (7 - uint(i%8))
+}
+
+// NewMnemonic encodes entropy as a mnemonic sentence.
+func NewMnemonic(entropy []byte) (string, error) {
+	entropyBits := len(entropy) * 8
+	if err := checkEntropyBits(entropyBits); err != nil {
+		return "", err
+	}
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+	defer zeroBytes(data)
+
+	words := make([]string, (entropyBits+entropyBits/32)/bitsPerWord)
+	for i := range words {
+		index := 0
+		for j := 0; j < bitsPerWord; j++ {
+			index = index<<1 | getBit(data, i*bitsPerWord+j)
+		}
+		words[i] = english[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// GenerateMnemonic creates a 24-word mnemonic with entropy from r.
+func GenerateMnemonic(r io.Reader) (string, error) {
+	entropy, err := NewEntropy(r, MnemonicEntropyBits)
+	if err != nil {
+		return "", err
+	}
+	defer zeroBytes(entropy)
+	return NewMnemonic(entropy)
+}
+
+// splitMnemonic normalizes a mnemonic and splits it into words.
+func splitMnemonic(mnemonic string) []string {
+	return strings.Fields(norm.NFKD.String(mnemonic))
+}
+
+func wordsToEntropy(words []string) ([]byte, error) {
+	totalBits := len(words) * bitsPerWord
+	entropyBits := totalBits * 32 / 33
+	if totalBits%33 != 0 || checkEntropyBits(entropyBits) != nil {
+		return nil, fmt.Errorf("%w: unexpected number of words %d", ErrInvalidMnemonic, len(words))
+	}
+
+	data := make([]byte, (totalBits+7)/8)
+	for i, w := range words {
+		index, ok := wordIndex[w]
+		if !ok {
+			zeroBytes(data)
+			return nil, fmt.Errorf("%w: word %d is not in the wordlist", ErrInvalidMnemonic, i+1)
+		}
+		for j := 0; j < bitsPerWord; j++ {
+			if index>>(bitsPerWord-1-uint(j))&1 == 1 {
+				setBit(data, i*bitsPerWord+j)
+			}
+		}
+	}
+	defer zeroBytes(data)
+
+	entropy := append([]byte{}, data[:entropyBits/8]...)
+	checksum := sha256.Sum256(entropy)
+	for i := 0; i < entropyBits/32; i++ {
+		if getBit(data, entropyBits+i) != getBit(checksum[:], i) {
+			zeroBytes(entropy)
+			return nil, ErrInvalidChecksum
+		}
+	}
+	return entropy, nil
+}
+
+// MnemonicToEntropy decodes a mnemonic sentence and checks its checksum.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	return wordsToEntropy(splitMnemonic(mnemonic))
+}
+
+// IsMnemonicValid checks if a mnemonic only has words from the wordlist
+// and a valid checksum.
+func IsMnemonicValid(mnemonic string) bool {
+	entropy, err := MnemonicToEntropy(mnemonic)
+	zeroBytes(entropy)
+	return err == nil
+}
+
+// NewSeed checks the mnemonic and derives the 64-byte seed from it and a
+// passphrase with PBKDF2-HMAC-SHA512.
+func NewSeed(mnemonic string, passphrase string) ([]byte, error) {
+	words := splitMnemonic(mnemonic)
+	entropy, err := wordsToEntropy(words)
+	if err != nil {
+		return nil, err
+	}
+	zeroBytes(entropy)
+
+	password := []byte(strings.Join(words, " "))
+	defer zeroBytes(password)
+	salt := []byte(seedSaltPrefix + norm.NFKD.String(passphrase))
+	defer zeroBytes(salt)
+	return pbkdf2.Key(password, salt, seedIterations, seedLength, sha512.New), nil
+}
+
+// MasterSecretKey derives the EIP-2333 master secret key of a mnemonic.
+// Keys for g2pubs can be created from its scalar with
+// g2pubs.NewSecretKeyFromScalar.
+func MasterSecretKey(mnemonic string, passphrase string) (*g1pubs.SecretKey, error) {
+	seed, err := NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(seed)
+	return eip2333.DeriveMasterSK(seed)
+}
+
+// DeriveSecretKey derives the EIP-2333 secret key of a mnemonic at an
+// EIP-2334 path such as eip2333.SigningKeyPath(0).
+func DeriveSecretKey(mnemonic string, passphrase string, path string) (*g1pubs.SecretKey, error) {
+	seed, err := NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(seed)
+	return eip2333.DerivePath(seed, path)
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,184 @@
+package bip39
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/bls/eip2333"
+)
+
+// Test vectors from the BIP-39 reference implementation, all with the
+// passphrase "TREZOR".
+var bip39Vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"80808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		"d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		"ffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote",
+		"dd48c104698c30cfe2b6142103248622fb7bb0ff692eebb00089b32d22484e1613912f0a5b694407be899ffd31ed3992c456cdf60f5d4564b8ba3f05a69890ad",
+	},
+	{
+		"68a79eaca2324873eacc50cb9c6eca8cc68ea5d936f98787c60c7ebc74e6ce7c",
+		"hamster diagram private dutch cause delay private meat slide toddler razor book happy fancy gospel tennis maple dilemma loan word shrug inflict delay length",
+		"64c87cde7e12ecf6704ab95bb1408bef047c22db4cc7491c4271d170a1b213d20b385bc1588d9c7b38f1b39d415665b8a9030c9ec653d75e65f847d8fc1fc440",
+	},
+}
+
+func TestWordlist(t *testing.T) {
+	if len(english) != 2048 {
+		t.Fatalf("expected 2048 words, got %d", len(english))
+	}
+	h := sha256.Sum256([]byte(strings.Join(english, "\n") + "\n"))
+	if hex.EncodeToString(h[:]) != "2f5eed53a4727b4bf8880d8f3f199efc90e58503646d9ff8eff3a2ed3b24dbda" {
+		t.Fatal("wordlist does not match the BIP-39 English wordlist")
+	}
+}
+
+func TestBIP39Vectors(t *testing.T) {
+	for i, v := range bip39Vectors {
+		entropy, _ := hex.DecodeString(v.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != v.mnemonic {
+			t.Fatalf("vector %d: expected mnemonic %q, got %q", i, v.mnemonic, mnemonic)
+		}
+
+		decoded, err := MnemonicToEntropy(v.mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, entropy) {
+			t.Fatalf("vector %d: entropy did not round trip", i)
+		}
+
+		seed, err := NewSeed(v.mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != v.seed {
+			t.Fatalf("vector %d: expected seed %s, got %x", i, v.seed, seed)
+		}
+	}
+}
+
+func TestInvalidMnemonic(t *testing.T) {
+	valid := bip39Vectors[0].mnemonic
+	if !IsMnemonicValid(valid) {
+		t.Fatal("expected mnemonic to be valid")
+	}
+	if !IsMnemonicValid("  " + strings.Replace(valid, " ", "\t ", 3) + "\n") {
+		t.Fatal("extra whitespace should be ignored")
+	}
+
+	badChecksum := strings.Replace(valid, "about", "abandon", 1)
+	if _, err := MnemonicToEntropy(badChecksum); !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("expected ErrInvalidChecksum, got %v", err)
+	}
+	unknownWord := strings.Replace(valid, "about", "aboot", 1)
+	if _, err := MnemonicToEntropy(unknownWord); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("expected ErrInvalidMnemonic, got %v", err)
+	}
+	if _, err := MnemonicToEntropy("ABANDON" + valid[7:]); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("expected ErrInvalidMnemonic for an upper case word, got %v", err)
+	}
+	for _, words := range []int{0, 11, 13, 27} {
+		m := strings.TrimSpace(strings.Repeat("abandon ", words))
+		if _, err := MnemonicToEntropy(m); !errors.Is(err, ErrInvalidMnemonic) {
+			t.Fatalf("expected ErrInvalidMnemonic for %d words, got %v", words, err)
+		}
+	}
+	if _, err := NewSeed(badChecksum, ""); !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("expected NewSeed to check the mnemonic, got %v", err)
+	}
+	if _, err := NewMnemonic(make([]byte, 15)); !errors.Is(err, ErrInvalidEntropyLength) {
+		t.Fatalf("expected ErrInvalidEntropyLength, got %v", err)
+	}
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	mnemonic, err := GenerateMnemonic(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strings.Fields(mnemonic)) != 24 {
+		t.Fatal("expected a 24-word mnemonic")
+	}
+	if !IsMnemonicValid(mnemonic) {
+		t.Fatal("generated mnemonic should be valid")
+	}
+	if _, err := NewEntropy(rand.Reader, 100); !errors.Is(err, ErrInvalidEntropyLength) {
+		t.Fatalf("expected ErrInvalidEntropyLength, got %v", err)
+	}
+}
+
+func TestPassphraseNormalization(t *testing.T) {
+	m := bip39Vectors[0].mnemonic
+	composed, _ := NewSeed(m, "caf\u00e9")
+	decomposed, _ := NewSeed(m, "cafe\u0301")
+	if !bytes.Equal(composed, decomposed) {
+		t.Fatal("passphrases should be NFKD normalized")
+	}
+}
+
+func TestMnemonicToSecretKey(t *testing.T) {
+	// the seed of the first vector is also the seed of the first
+	// EIP-2333 test vector
+	m := bip39Vectors[0].mnemonic
+	master, err := MasterSecretKey(m, "TREZOR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := master.Serialize()
+	if new(big.Int).SetBytes(b[:]).String() != "6083874454709270928345386274498605044986640685124978867557563392430687146096" {
+		t.Fatal("master key does not match the EIP-2333 test vector")
+	}
+
+	sk, err := DeriveSecretKey(m, "TREZOR", eip2333.SigningKeyPath(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, _ := NewSeed(m, "TREZOR")
+	expected, _ := eip2333.DerivePath(seed, "m/12381/3600/3/0/0")
+	if sk.Serialize() != expected.Serialize() {
+		t.Fatal("derived key does not match the EIP-2333 path")
+	}
+
+	if _, err := DeriveSecretKey(m, "TREZOR", "m/x"); !errors.Is(err, eip2333.ErrInvalidPath) {
+		t.Fatalf("expected ErrInvalidPath, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Command bls-signer is a remote signing daemon that serves the eth2
+// endpoints of the Web3Signer HTTP API for keys loaded from EIP-2335
+// keystores.
+//
+// The signer computes the signing root of every request from the signed
+// object and the fork info, and signs it with the
+// BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_ ciphersuite of Ethereum
+// validators. It supports the BLOCK_V2 (with a block_header),
+// ATTESTATION, AGGREGATE_AND_PROOF, AGGREGATION_SLOT, RANDAO_REVEAL,
+// SYNC_COMMITTEE_MESSAGE, SYNC_COMMITTEE_SELECTION_PROOF,
+// SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF and VOLUNTARY_EXIT types, with
+// phase0 attestations in aggregates. Other types, such as BLOCK, DEPOSIT,
+// VALIDATOR_REGISTRATION and AGGREGATE_AND_PROOF_V2, are refused, so the
+// signer is not a drop-in replacement for Web3Signer.
+//
+// Blocks and attestations are checked against an EIP-3076 slashing
+// protection file, which is required unless slashing protection is
+// explicitly disabled.
+//
+//	bls-signer -keystores keys/ -passwords passwords/ \
+//		-slashing-protection slashing.json -genesis-validators-root 0x...
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/slashing"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:9000", "address to listen on")
+	keystoreDir := flag.String("keystores", "", "directory of EIP-2335 keystore files")
+	passwordDir := flag.String("passwords", "", "directory of password files named after the keystores (defaults to -keystores)")
+	protectionPath := flag.String("slashing-protection", "", "EIP-3076 slashing protection file")
+	noProtection := flag.Bool("insecure-disable-slashing-protection", false, "run without slashing protection instead of requiring -slashing-protection")
+	gvrHex := flag.String("genesis-validators-root", "", "genesis validators root of the chain")
+	flag.Parse()
+
+	if *keystoreDir == "" {
+		log.Fatal("-keystores is required")
+	}
+	if *protectionPath == "" && !*noProtection {
+		log.Fatal("-slashing-protection is required unless -insecure-disable-slashing-protection is given")
+	}
+	b, err := bls.DecodeHex([]byte(*gvrHex), 32)
+	if err != nil {
+		log.Fatalf("-genesis-validators-root: %v", err)
+	}
+	var gvr [32]byte
+	copy(gvr[:], b)
+	if *passwordDir == "" {
+		*passwordDir = *keystoreDir
+	}
+	keys, err := loadKeys(*keystoreDir, *passwordDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var protection *slashing.Store
+	if *protectionPath != "" {
+		protection, err = slashing.Open(*protectionPath, gvr)
+		if err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		log.Print("WARNING: slashing protection is disabled")
+	}
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           newServer(keys, protection, gvr).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	log.Printf("serving %d keys on %s", len(keys), *listen)
+	log.Fatal(srv.ListenAndServe())
+}
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/g1pubs"
+	"github.com/phoreproject/bls/keystore"
+	"github.com/phoreproject/bls/slashing"
+)
+
+const (
+	publicKeysPath = "/api/v1/eth2/publicKeys"
+	signPathPrefix = "/api/v1/eth2/sign/"
+	upcheckPath    = "/upcheck"
+	healthPath     = "/healthcheck"
+
+	maxRequestSize = 1 << 20
+)
+
+// Signing request types. The signer computes the signing root of each of
+// them from the signed object, and refuses any other type.
+const (
+	typeBlockV2                           = "BLOCK_V2"
+	typeAttestation                       = "ATTESTATION"
+	typeRandaoReveal                      = "RANDAO_REVEAL"
+	typeAggregationSlot                   = "AGGREGATION_SLOT"
+	typeAggregateAndProof                 = "AGGREGATE_AND_PROOF"
+	typeSyncCommitteeMessage              = "SYNC_COMMITTEE_MESSAGE"
+	typeSyncCommitteeSelectionProof       = "SYNC_COMMITTEE_SELECTION_PROOF"
+	typeSyncCommitteeContributionAndProof = "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF"
+	typeVoluntaryExit                     = "VOLUNTARY_EXIT"
+)
+
+// ethDST is the tag of the ciphersuite of Ethereum validator signatures.
+var ethDST = []byte(g1pubs.CiphersuitePOP)
+
+// errBadRequest is returned for signing requests that are unsupported,
+// malformed or whose signing root does not match the signed object.
+var errBadRequest = errors.New("bad signing request")
+
+// root is a 32 byte root in hex.
+type root [32]byte
+
+func (r *root) UnmarshalText(text []byte) error {
+	b, err := bls.DecodeHex(text, 32)
+	if err != nil {
+		return err
+	}
+	copy(r[:], b)
+	return nil
+}
+
+// version is a 4 byte fork version in hex.
+type version [4]byte
+
+func (v *version) UnmarshalText(text []byte) error {
+	b, err := bls.DecodeHex(text, 4)
+	if err != nil {
+		return err
+	}
+	copy(v[:], b)
+	return nil
+}
+
+// signature is a 96 byte BLS signature in hex.
+type signature [96]byte
+
+func (sig *signature) UnmarshalText(text []byte) error {
+	b, err := bls.DecodeHex(text, 96)
+	if err != nil {
+		return err
+	}
+	copy(sig[:], b)
+	return nil
+}
+
+// attestationBits is the SSZ encoding of the aggregation bits of a phase0
+// attestation, a bitlist of at most maxValidatorsPerCommittee bits that
+// ends with a length bit.
+type attestationBits []byte
+
+func (a *attestationBits) UnmarshalText(text []byte) error {
+	n := len(text)
+	if n >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		n -= 2
+	}
+	b, err := bls.DecodeHex(text, n/2)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 || b[len(b)-1] == 0 {
+		return errors.New("bitlist is missing its length bit")
+	}
+	if bitlistLen(b) > maxValidatorsPerCommittee {
+		return fmt.Errorf("bitlist is longer than %d bits", maxValidatorsPerCommittee)
+	}
+	*a = b
+	return nil
+}
+
+// syncCommitteeBits is the aggregation bitvector of a sync committee
+// contribution in hex.
+type syncCommitteeBits [syncSubcommitteeSize / 8]byte
+
+func (s *syncCommitteeBits) UnmarshalText(text []byte) error {
+	b, err := bls.DecodeHex(text, len(s))
+	if err != nil {
+		return err
+	}
+	copy(s[:], b)
+	return nil
+}
+
+type forkInfo struct {
+	Fork struct {
+		PreviousVersion version `json:"previous_version"`
+		CurrentVersion  version `json:"current_version"`
+		Epoch           uint64  `json:"epoch,string"`
+	} `json:"fork"`
+	GenesisValidatorsRoot root `json:"genesis_validators_root"`
+}
+
+// domain implements get_domain of the consensus specs for the fork.
+func (f *forkInfo) domain(domainType [4]byte, epoch uint64) [32]byte {
+	v := f.Fork.CurrentVersion
+	if epoch < f.Fork.Epoch {
+		v = f.Fork.PreviousVersion
+	}
+	return computeDomain(domainType, v, f.GenesisValidatorsRoot)
+}
+
+type checkpoint struct {
+	Epoch uint64 `json:"epoch,string"`
+	Root  root   `json:"root"`
+}
+
+type beaconBlockHeader struct {
+	Slot          uint64 `json:"slot,string"`
+	ProposerIndex uint64 `json:"proposer_index,string"`
+	ParentRoot    root   `json:"parent_root"`
+	StateRoot     root   `json:"state_root"`
+	BodyRoot      root   `json:"body_root"`
+}
+
+type attestationData struct {
+	Slot            uint64     `json:"slot,string"`
+	Index           uint64     `json:"index,string"`
+	BeaconBlockRoot root       `json:"beacon_block_root"`
+	Source          checkpoint `json:"source"`
+	Target          checkpoint `json:"target"`
+}
+
+type attestation struct {
+	AggregationBits attestationBits `json:"aggregation_bits"`
+	Data            attestationData `json:"data"`
+	Signature       signature       `json:"signature"`
+}
+
+type aggregateAndProof struct {
+	AggregatorIndex uint64      `json:"aggregator_index,string"`
+	Aggregate       attestation `json:"aggregate"`
+	SelectionProof  signature   `json:"selection_proof"`
+}
+
+type syncAggregatorSelectionData struct {
+	Slot              uint64 `json:"slot,string"`
+	SubcommitteeIndex uint64 `json:"subcommittee_index,string"`
+}
+
+type syncCommitteeContribution struct {
+	Slot              uint64            `json:"slot,string"`
+	BeaconBlockRoot   root              `json:"beacon_block_root"`
+	SubcommitteeIndex uint64            `json:"subcommittee_index,string"`
+	AggregationBits   syncCommitteeBits `json:"aggregation_bits"`
+	Signature         signature         `json:"signature"`
+}
+
+type contributionAndProof struct {
+	AggregatorIndex uint64                    `json:"aggregator_index,string"`
+	Contribution    syncCommitteeContribution `json:"contribution"`
+	SelectionProof  signature                 `json:"selection_proof"`
+}
+
+type voluntaryExit struct {
+	Epoch          uint64 `json:"epoch,string"`
+	ValidatorIndex uint64 `json:"validator_index,string"`
+}
+
+// signRequest holds the fields of a Web3Signer eth2 signing request that
+// the signer uses. The signing root is optional; if it is given, it must
+// match the signing root computed from the signed object.
+type signRequest struct {
+	Type        string    `json:"type"`
+	SigningRoot string    `json:"signingRoot"`
+	ForkInfo    *forkInfo `json:"fork_info"`
+	BeaconBlock *struct {
+		BlockHeader *beaconBlockHeader `json:"block_header"`
+	} `json:"beacon_block"`
+	Attestation  *attestationData `json:"attestation"`
+	RandaoReveal *struct {
+		Epoch uint64 `json:"epoch,string"`
+	} `json:"randao_reveal"`
+	AggregationSlot *struct {
+		Slot uint64 `json:"slot,string"`
+	} `json:"aggregation_slot"`
+	AggregateAndProof    *aggregateAndProof `json:"aggregate_and_proof"`
+	SyncCommitteeMessage *struct {
+		BeaconBlockRoot root   `json:"beacon_block_root"`
+		Slot            uint64 `json:"slot,string"`
+	} `json:"sync_committee_message"`
+	SyncAggregatorSelectionData *syncAggregatorSelectionData `json:"sync_aggregator_selection_data"`
+	ContributionAndProof        *contributionAndProof        `json:"contribution_and_proof"`
+	VoluntaryExit               *voluntaryExit               `json:"voluntary_exit"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature"`
+}
+
+type server struct {
+	keys                  map[string]*g1pubs.SecretKey
+	publicKeys            []string
+	protection            *slashing.Store
+	genesisValidatorsRoot string
+}
+
+func publicKeyHex(pub *g1pubs.PublicKey) string {
+	b := pub.Serialize()
+	return string(bls.EncodeHex(b[:]))
+}
+
+// newServer creates a signer for the keys on the chain with the given
+// genesis validators root. If protection is not nil, blocks and
+// attestations are checked against it.
+func newServer(keys []*g1pubs.SecretKey, protection *slashing.Store, genesisValidatorsRoot [32]byte) *server {
+	s := &server{
+		keys:                  make(map[string]*g1pubs.SecretKey, len(keys)),
+		protection:            protection,
+		genesisValidatorsRoot: string(bls.EncodeHex(genesisValidatorsRoot[:])),
+	}
+	for _, k := range keys {
+		pub := publicKeyHex(g1pubs.PrivToPub(k))
+		if _, ok := s.keys[pub]; !ok {
+			s.publicKeys = append(s.publicKeys, pub)
+		}
+		s.keys[pub] = k
+	}
+	return s
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(publicKeysPath, s.handlePublicKeys)
+	mux.HandleFunc(signPathPrefix, s.handleSign)
+	mux.HandleFunc(upcheckPath, s.handleUpcheck)
+	mux.HandleFunc(healthPath, s.handleHealth)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func requireMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return false
+	}
+	return true
+}
+
+func (s *server) handleUpcheck(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "OK")
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "UP"})
+}
+
+func (s *server) handlePublicKeys(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodGet) {
+		return
+	}
+	keys := s.publicKeys
+	if keys == nil {
+		keys = []string{}
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (s *server) handleSign(w http.ResponseWriter, r *http.Request) {
+	if !requireMethod(w, r, http.MethodPost) {
+		return
+	}
+	var pub g1pubs.PublicKey
+	if err := pub.UnmarshalText([]byte(strings.TrimPrefix(r.URL.Path, signPathPrefix))); err != nil {
+		writeError(w, http.StatusNotFound, "public key not found")
+		return
+	}
+	key, ok := s.keys[publicKeyHex(&pub)]
+	if !ok {
+		writeError(w, http.StatusNotFound, "public key not found")
+		return
+	}
+
+	var req signRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid signing request: "+err.Error())
+		return
+	}
+	signingRoot, err := s.signingRoot(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sig, err := s.sign(key, &req, signingRoot)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, slashing.ErrSlashableBlock) || errors.Is(err, slashing.ErrSlashableAttestation) {
+			status = http.StatusPreconditionFailed
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	b := sig.Serialize()
+	sigHex := string(bls.EncodeHex(b[:]))
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		writeJSON(w, http.StatusOK, signResponse{Signature: sigHex})
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, sigHex)
+}
+
+// signingRoot computes the signing root of the signed object of a
+// request and checks it against the signing root given in the request.
+func (s *server) signingRoot(req *signRequest) ([32]byte, error) {
+	var zero [32]byte
+	if req.ForkInfo == nil {
+		return zero, fmt.Errorf("%w: fork_info is required", errBadRequest)
+	}
+	if string(bls.EncodeHex(req.ForkInfo.GenesisValidatorsRoot[:])) != s.genesisValidatorsRoot {
+		return zero, fmt.Errorf("%w: genesis validators root does not match", errBadRequest)
+	}
+
+	var objectRoot, domain [32]byte
+	switch {
+	case req.Type == typeBlockV2 && req.BeaconBlock != nil && req.BeaconBlock.BlockHeader != nil:
+		h := req.BeaconBlock.BlockHeader
+		objectRoot = h.hashTreeRoot()
+		domain = req.ForkInfo.domain(domainBeaconProposer, h.Slot/slotsPerEpoch)
+	case req.Type == typeAttestation && req.Attestation != nil:
+		objectRoot = req.Attestation.hashTreeRoot()
+		domain = req.ForkInfo.domain(domainBeaconAttester, req.Attestation.Target.Epoch)
+	case req.Type == typeRandaoReveal && req.RandaoReveal != nil:
+		objectRoot = uint64Root(req.RandaoReveal.Epoch)
+		domain = req.ForkInfo.domain(domainRandao, req.RandaoReveal.Epoch)
+	case req.Type == typeAggregationSlot && req.AggregationSlot != nil:
+		objectRoot = uint64Root(req.AggregationSlot.Slot)
+		domain = req.ForkInfo.domain(domainSelectionProof, req.AggregationSlot.Slot/slotsPerEpoch)
+	case req.Type == typeAggregateAndProof && req.AggregateAndProof != nil:
+		objectRoot = req.AggregateAndProof.hashTreeRoot()
+		domain = req.ForkInfo.domain(domainAggregateAndProof, req.AggregateAndProof.Aggregate.Data.Slot/slotsPerEpoch)
+	case req.Type == typeSyncCommitteeMessage && req.SyncCommitteeMessage != nil:
+		objectRoot = req.SyncCommitteeMessage.BeaconBlockRoot
+		domain = req.ForkInfo.domain(domainSyncCommittee, req.SyncCommitteeMessage.Slot/slotsPerEpoch)
+	case req.Type == typeSyncCommitteeSelectionProof && req.SyncAggregatorSelectionData != nil:
+		objectRoot = req.SyncAggregatorSelectionData.hashTreeRoot()
+		domain = req.ForkInfo.domain(domainSyncCommitteeSelectionProof, req.SyncAggregatorSelectionData.Slot/slotsPerEpoch)
+	case req.Type == typeSyncCommitteeContributionAndProof && req.ContributionAndProof != nil:
+		objectRoot = req.ContributionAndProof.hashTreeRoot()
+		domain = req.ForkInfo.domain(domainContributionAndProof, req.ContributionAndProof.Contribution.Slot/slotsPerEpoch)
+	case req.Type == typeVoluntaryExit && req.VoluntaryExit != nil:
+		objectRoot = req.VoluntaryExit.hashTreeRoot()
+		domain = req.ForkInfo.domain(domainVoluntaryExit, req.VoluntaryExit.Epoch)
+	default:
+		switch req.Type {
+		case typeBlockV2, typeAttestation, typeRandaoReveal, typeAggregationSlot, typeAggregateAndProof,
+			typeSyncCommitteeMessage, typeSyncCommitteeSelectionProof, typeSyncCommitteeContributionAndProof, typeVoluntaryExit:
+			return zero, fmt.Errorf("%w: %s request is missing the signed object", errBadRequest, req.Type)
+		}
+		return zero, fmt.Errorf("%w: unsupported type %q", errBadRequest, req.Type)
+	}
+	signingRoot := computeSigningRoot(objectRoot, domain)
+
+	if req.SigningRoot != "" {
+		given, err := bls.DecodeHex([]byte(req.SigningRoot), 32)
+		if err != nil {
+			return zero, fmt.Errorf("%w: invalid signing root", errBadRequest)
+		}
+		if string(given) != string(signingRoot[:]) {
+			return zero, fmt.Errorf("%w: signing root does not match the signed object", errBadRequest)
+		}
+	}
+	return signingRoot, nil
+}
+
+// sign signs the signing root with the ciphersuite of Ethereum validator
+// signatures, going through slashing protection for blocks and
+// attestations if it is enabled.
+func (s *server) sign(key *g1pubs.SecretKey, req *signRequest, signingRoot [32]byte) (*g1pubs.Signature, error) {
+	if s.protection != nil {
+		switch req.Type {
+		case typeBlockV2:
+			return s.protection.SignBlock(key, req.BeaconBlock.BlockHeader.Slot, signingRoot)
+		case typeAttestation:
+			return s.protection.SignAttestation(key, req.Attestation.Source.Epoch, req.Attestation.Target.Epoch, signingRoot)
+		}
+	}
+	return g1pubs.SignWithDST(signingRoot[:], key, ethDST)
+}
+
+// loadKeys decrypts the EIP-2335 keystores in keystoreDir. The password of
+// name.json is read from name.txt in passwordDir.
+func loadKeys(keystoreDir string, passwordDir string) ([]*g1pubs.SecretKey, error) {
+	paths, err := filepath.Glob(filepath.Join(keystoreDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*g1pubs.SecretKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var ks keystore.Keystore
+		if err := json.Unmarshal(data, &ks); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		password, err := ioutil.ReadFile(filepath.Join(passwordDir, name+".txt"))
+		if err != nil {
+			return nil, err
+		}
+		key, err := ks.Decrypt(strings.TrimRight(string(password), "\r\n"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/g1pubs"
+	"github.com/phoreproject/bls/keystore"
+	"github.com/phoreproject/bls/slashing"
+)
+
+var testGenesisValidatorsRoot = [32]byte{0x04, 0x70, 0x0e}
+
+var testForkInfo = `{
+	"fork": {"previous_version": "0x00000000", "current_version": "0x00000000", "epoch": "0"},
+	"genesis_validators_root": "` + string(bls.EncodeHex(testGenesisValidatorsRoot[:])) + `"
+}`
+
+func writeKeystores(t *testing.T, dir string, n int) []*g1pubs.SecretKey {
+	keys := make([]*g1pubs.SecretKey, n)
+	for i := range keys {
+		keys[i], _ = g1pubs.RandKey(rand.Reader)
+		ks, err := keystore.Encrypt(keys[i], "password", &keystore.Options{KDF: keystore.KDFPBKDF2, Cost: 16}, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(ks)
+		name := filepath.Join(dir, "keystore-"+string(rune('a'+i)))
+		if err := ioutil.WriteFile(name+".json", data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(name+".txt", []byte("password\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+type testSigner struct {
+	*httptest.Server
+	dir  string
+	keys []*g1pubs.SecretKey
+}
+
+func startSigner(t *testing.T, protect bool) *testSigner {
+	dir, err := ioutil.TempDir("", "bls-signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := writeKeystores(t, dir, 2)
+	loaded, err := loadKeys(dir, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != len(keys) {
+		t.Fatalf("expected %d keys, loaded %d", len(keys), len(loaded))
+	}
+
+	var protection *slashing.Store
+	if protect {
+		protection, err = slashing.Open(filepath.Join(dir, "slashing.json"), testGenesisValidatorsRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(newServer(loaded, protection, testGenesisValidatorsRoot).handler())
+	return &testSigner{Server: srv, dir: dir, keys: keys}
+}
+
+func (s *testSigner) stop() {
+	s.Close()
+	os.RemoveAll(s.dir)
+}
+
+func pubHex(k *g1pubs.SecretKey) string {
+	return publicKeyHex(g1pubs.PrivToPub(k))
+}
+
+func (s *testSigner) sign(t *testing.T, pub string, body string, accept string) (int, string) {
+	req, _ := http.NewRequest(http.MethodPost, s.URL+signPathPrefix+pub, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	out, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(out)
+}
+
+func checkSignature(t *testing.T, k *g1pubs.SecretKey, root [32]byte, sigHex string) {
+	var sig g1pubs.Signature
+	if err := sig.UnmarshalText([]byte(sigHex)); err != nil {
+		t.Fatal(err)
+	}
+	// Signatures must use the ciphersuite of Ethereum validators.
+	dst := []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+	if err := g1pubs.VerifyWithDST(root[:], g1pubs.PrivToPub(k), &sig, dst); err != nil {
+		t.Fatalf("signature from the signer does not verify: %v", err)
+	}
+}
+
+// Signed objects of the test requests, with their hash tree roots
+// computed independently of the signer.
+var (
+	testAttestationData = `{
+		"slot": "70", "index": "1", "beacon_block_root": "` + testRoot(5) + `",
+		"source": {"epoch": "1", "root": "` + testRoot(6) + `"},
+		"target": {"epoch": "2", "root": "` + testRoot(7) + `"}
+	}`
+	testAggregateAndProof = `{
+		"aggregator_index": "9",
+		"aggregate": {"aggregation_bits": "0x0560", "data": ` + testAttestationData + `, "signature": "` + testSignature(0xaa) + `"},
+		"selection_proof": "` + testSignature(0xbb) + `"
+	}`
+	testContributionAndProof = `{
+		"aggregator_index": "9",
+		"contribution": {
+			"slot": "70", "beacon_block_root": "` + testRoot(5) + `", "subcommittee_index": "2",
+			"aggregation_bits": "0x0f` + strings.Repeat("00", 15) + `", "signature": "` + testSignature(0xaa) + `"
+		},
+		"selection_proof": "` + testSignature(0xbb) + `"
+	}`
+)
+
+func testRoot(b byte) string {
+	return fmt.Sprintf("0x%02x", b) + strings.Repeat("00", 31)
+}
+
+func testSignature(b byte) string {
+	return "0x" + strings.Repeat(fmt.Sprintf("%02x", b), 96)
+}
+
+func TestSignerDuties(t *testing.T) {
+	s := startSigner(t, false)
+	defer s.stop()
+	pub := pubHex(s.keys[0])
+	var fi forkInfo
+	fi.GenesisValidatorsRoot = testGenesisValidatorsRoot
+
+	var aap aggregateAndProof
+	var contribution contributionAndProof
+	if err := json.Unmarshal([]byte(testAggregateAndProof), &aap); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(testContributionAndProof), &contribution); err != nil {
+		t.Fatal(err)
+	}
+	selection := syncAggregatorSelectionData{Slot: 70, SubcommitteeIndex: 2}
+	exit := voluntaryExit{Epoch: 3, ValidatorIndex: 12}
+
+	duties := []struct {
+		body string
+		root [32]byte
+	}{
+		{`"type": "AGGREGATE_AND_PROOF", "aggregate_and_proof": ` + testAggregateAndProof,
+			computeSigningRoot(aap.hashTreeRoot(), fi.domain(domainAggregateAndProof, 2))},
+		{`"type": "SYNC_COMMITTEE_SELECTION_PROOF", "sync_aggregator_selection_data": {"slot": "70", "subcommittee_index": "2"}`,
+			computeSigningRoot(selection.hashTreeRoot(), fi.domain(domainSyncCommitteeSelectionProof, 2))},
+		{`"type": "SYNC_COMMITTEE_CONTRIBUTION_AND_PROOF", "contribution_and_proof": ` + testContributionAndProof,
+			computeSigningRoot(contribution.hashTreeRoot(), fi.domain(domainContributionAndProof, 2))},
+		{`"type": "VOLUNTARY_EXIT", "voluntary_exit": {"epoch": "3", "validator_index": "12"}`,
+			computeSigningRoot(exit.hashTreeRoot(), fi.domain(domainVoluntaryExit, 3))},
+		{`"type": "RANDAO_REVEAL", "randao_reveal": {"epoch": "3"}`,
+			computeSigningRoot(uint64Root(3), fi.domain(domainRandao, 3))},
+		{`"type": "SYNC_COMMITTEE_MESSAGE", "sync_committee_message": {"beacon_block_root": "` + testRoot(5) + `", "slot": "70"}`,
+			computeSigningRoot([32]byte{5}, fi.domain(domainSyncCommittee, 2))},
+	}
+	for _, d := range duties {
+		status, out := s.sign(t, pub, `{"fork_info": `+testForkInfo+`, `+d.body+`}`, "")
+		if status != http.StatusOK {
+			t.Fatalf("unexpected status %d for %s: %s", status, d.body, out)
+		}
+		checkSignature(t, s.keys[0], d.root, out)
+	}
+
+	badBits := []string{"0x", "0x00", "0x0500", "0x" + strings.Repeat("ff", 256) + "03"}
+	for _, bits := range badBits {
+		body := strings.Replace(testAggregateAndProof, `"0x0560"`, `"`+bits+`"`, 1)
+		if status, _ := s.sign(t, pub, `{"type": "AGGREGATE_AND_PROOF", "fork_info": `+testForkInfo+`, "aggregate_and_proof": `+body+`}`, ""); status != http.StatusBadRequest {
+			t.Fatalf("expected 400 for aggregation bits %s, got %d", bits, status)
+		}
+	}
+}
+
+func TestSignerEndpoints(t *testing.T) {
+	s := startSigner(t, false)
+	defer s.stop()
+
+	resp, err := s.Client().Get(s.URL + upcheckPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "OK" {
+		t.Fatalf("unexpected upcheck response %d %q", resp.StatusCode, body)
+	}
+
+	resp, err = s.Client().Get(s.URL + publicKeysPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pubs []string
+	if err := json.NewDecoder(resp.Body).Decode(&pubs); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(pubs) != 2 {
+		t.Fatalf("expected 2 public keys, got %d", len(pubs))
+	}
+	for _, k := range s.keys {
+		if pubs[0] != pubHex(k) && pubs[1] != pubHex(k) {
+			t.Fatal("public key missing from the list")
+		}
+	}
+
+	var fi forkInfo
+	fi.GenesisValidatorsRoot = testGenesisValidatorsRoot
+	root := computeSigningRoot(uint64Root(70), fi.domain(domainSelectionProof, 2))
+	body2 := `{"type": "AGGREGATION_SLOT", "fork_info": ` + testForkInfo + `, "aggregation_slot": {"slot": "70"}}`
+
+	status, out := s.sign(t, pubHex(s.keys[0]), body2, "")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", status, out)
+	}
+	checkSignature(t, s.keys[0], root, out)
+
+	status, out = s.sign(t, pubHex(s.keys[1]), body2, "application/json")
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", status, out)
+	}
+	var jsonResp signResponse
+	if err := json.Unmarshal([]byte(out), &jsonResp); err != nil {
+		t.Fatal(err)
+	}
+	checkSignature(t, s.keys[1], root, jsonResp.Signature)
+
+	withRoot := func(r [32]byte) string {
+		return `{"type": "AGGREGATION_SLOT", "signingRoot": "` + string(bls.EncodeHex(r[:])) + `", "fork_info": ` + testForkInfo + `, "aggregation_slot": {"slot": "70"}}`
+	}
+	if status, out := s.sign(t, pubHex(s.keys[0]), withRoot(root), ""); status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", status, out)
+	}
+	if status, _ := s.sign(t, pubHex(s.keys[0]), withRoot([32]byte{1, 2, 3}), ""); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a mismatched signing root, got %d", status)
+	}
+
+	other, _ := g1pubs.RandKey(rand.Reader)
+	if status, _ := s.sign(t, pubHex(other), body2, ""); status != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown key, got %d", status)
+	}
+	if status, _ := s.sign(t, "0x1234", body2, ""); status != http.StatusNotFound {
+		t.Fatalf("expected 404 for a malformed key, got %d", status)
+	}
+	badRequests := []string{
+		`{`,
+		`{"type": "AGGREGATION_SLOT", "signingRoot": "0x12", "fork_info": ` + testForkInfo + `, "aggregation_slot": {"slot": "70"}}`,
+		`{"type": "AGGREGATION_SLOT", "aggregation_slot": {"slot": "70"}}`,
+		`{"type": "AGGREGATION_SLOT", "fork_info": ` + testForkInfo + `}`,
+		`{"type": "BLOCK", "fork_info": ` + testForkInfo + `, "block": {"slot": "70"}}`,
+		`{"type": "AGGREGATE_AND_PROOF", "fork_info": ` + testForkInfo + `}`,
+		`{"type": "DEPOSIT", "fork_info": ` + testForkInfo + `}`,
+		`{"fork_info": ` + testForkInfo + `}`,
+	}
+	for _, body := range badRequests {
+		if status, _ := s.sign(t, pubHex(s.keys[0]), body, ""); status != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", body, status)
+		}
+	}
+
+	resp, err = s.Client().Post(s.URL+publicKeysPath, "application/json", bytes.NewReader(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", resp.StatusCode)
+	}
+}
+
+func TestSignerSlashingProtection(t *testing.T) {
+	s := startSigner(t, true)
+	defer s.stop()
+	pub := pubHex(s.keys[0])
+	var fi forkInfo
+	fi.GenesisValidatorsRoot = testGenesisValidatorsRoot
+
+	block := func(slot uint64, bodyRoot byte) (int, string) {
+		return s.sign(t, pub, `{
+			"type": "BLOCK_V2",
+			"fork_info": `+testForkInfo+`,
+			"beacon_block": {"version": "PHASE0", "block_header": {
+				"slot": "`+strconv.FormatUint(slot, 10)+`",
+				"proposer_index": "3",
+				"parent_root": "0x`+strings.Repeat("00", 32)+`",
+				"state_root": "0x`+strings.Repeat("00", 32)+`",
+				"body_root": "0x`+fmt.Sprintf("%02x", bodyRoot)+strings.Repeat("00", 31)+`"
+			}}
+		}`, "")
+	}
+	status, out := block(10, 1)
+	if status != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", status, out)
+	}
+	h := beaconBlockHeader{Slot: 10, ProposerIndex: 3, BodyRoot: root{1}}
+	checkSignature(t, s.keys[0], computeSigningRoot(h.hashTreeRoot(), fi.domain(domainBeaconProposer, 0)), out)
+	if status, _ := block(10, 1); status != http.StatusOK {
+		t.Fatalf("expected repeat signing to succeed, got %d", status)
+	}
+	if status, _ := block(10, 2); status != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a double proposal, got %d", status)
+	}
+
+	attestation := func(source, target uint64, blockRoot byte) int {
+		status, _ := s.sign(t, pub, `{
+			"type": "ATTESTATION",
+			"fork_info": `+testForkInfo+`,
+			"attestation": {
+				"slot": "`+strconv.FormatUint(target*slotsPerEpoch, 10)+`",
+				"index": "0",
+				"beacon_block_root": "0x`+fmt.Sprintf("%02x", blockRoot)+strings.Repeat("00", 31)+`",
+				"source": {"epoch": "`+strconv.FormatUint(source, 10)+`", "root": "0x`+strings.Repeat("00", 32)+`"},
+				"target": {"epoch": "`+strconv.FormatUint(target, 10)+`", "root": "0x`+strings.Repeat("00", 32)+`"}
+			}
+		}`, "")
+		return status
+	}
+	if status := attestation(2, 5, 1); status != http.StatusOK {
+		t.Fatalf("unexpected status %d", status)
+	}
+	if status := attestation(2, 5, 2); status != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a double vote, got %d", status)
+	}
+	if status := attestation(1, 6, 1); status != http.StatusPreconditionFailed {
+		t.Fatalf("expected 412 for a surrounding vote, got %d", status)
+	}
+
+	missing := `{"type": "ATTESTATION", "fork_info": ` + testForkInfo + `}`
+	if status, _ := s.sign(t, pub, missing, ""); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 without the attestation, got %d", status)
+	}
+	wrongChain := strings.Replace(`{"type": "RANDAO_REVEAL", "fork_info": `+testForkInfo+`, "randao_reveal": {"epoch": "1"}}`,
+		string(bls.EncodeHex(testGenesisValidatorsRoot[:])), "0x"+strings.Repeat("00", 32), 1)
+	if status, _ := s.sign(t, pub, wrongChain, ""); status != http.StatusBadRequest {
+		t.Fatalf("expected 400 for another chain, got %d", status)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.dir, "slashing.json")); err != nil {
+		t.Fatal("slashing protection should be written to disk")
+	}
+}
+
+func TestSigningRoot(t *testing.T) {
+	deposit := computeDomain([4]byte{0x03}, [4]byte{}, [32]byte{})
+	if got := string(bls.EncodeHex(deposit[:])); got != "0x03000000f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a9" {
+		t.Fatalf("unexpected deposit domain %s", got)
+	}
+
+	var fi forkInfo
+	fi.Fork.CurrentVersion = version{0x01}
+	fi.GenesisValidatorsRoot = testGenesisValidatorsRoot
+	h := beaconBlockHeader{Slot: 10, ProposerIndex: 3, ParentRoot: root{1}, StateRoot: root{2}, BodyRoot: root{3}}
+	objectRoot := h.hashTreeRoot()
+	if got := string(bls.EncodeHex(objectRoot[:])); got != "0x05a2d9e98149ad8351c65c677a23b7ca73584c6b8018ddd3c3c22885c5ec73db" {
+		t.Fatalf("unexpected block header root %s", got)
+	}
+	signingRoot := computeSigningRoot(objectRoot, fi.domain(domainBeaconProposer, 0))
+	if got := string(bls.EncodeHex(signingRoot[:])); got != "0x2a47619abfffa53f265d5308f5976831ace261a17bcf732848b3367837507040" {
+		t.Fatalf("unexpected signing root %s", got)
+	}
+
+	var aap aggregateAndProof
+	if err := json.Unmarshal([]byte(testAggregateAndProof), &aap); err != nil {
+		t.Fatal(err)
+	}
+	var contribution contributionAndProof
+	if err := json.Unmarshal([]byte(testContributionAndProof), &contribution); err != nil {
+		t.Fatal(err)
+	}
+	fullByte := aap
+	if err := fullByte.Aggregate.AggregationBits.UnmarshalText([]byte("0xff01")); err != nil {
+		t.Fatal(err)
+	}
+	selection := syncAggregatorSelectionData{Slot: 70, SubcommitteeIndex: 2}
+	exit := voluntaryExit{Epoch: 3, ValidatorIndex: 12}
+	for _, c := range []struct {
+		name     string
+		root     [32]byte
+		expected string
+	}{
+		{"aggregate and proof", aap.hashTreeRoot(), "0x2c62ac373969c687a4a5336f94bdaf54ddb95fdb467a94f6a8d239319368be6a"},
+		{"aggregate and proof with 8 bits", fullByte.hashTreeRoot(), "0xd7450f7d907560f2f38a132c8993e4be602e1b7ddd412ad1f3fb5f929b58cc46"},
+		{"contribution and proof", contribution.hashTreeRoot(), "0x8c8a6a9cebe577f1505fe4755079d1355aa4cb706335dbfa87d6b23c917bbe44"},
+		{"sync aggregator selection data", selection.hashTreeRoot(), "0xb64cf2381733a5bb7b7d1ae1a9d94ccaa193a5d865da2d9a92df365a649f099b"},
+		{"voluntary exit", exit.hashTreeRoot(), "0x6ba39e01845797d74ca0db7f1b419d747062ce83174f178cd14f842f60bfc921"},
+	} {
+		if got := string(bls.EncodeHex(c.root[:])); got != c.expected {
+			t.Fatalf("unexpected %s root %s", c.name, got)
+		}
+	}
+}
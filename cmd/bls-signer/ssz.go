@@ -0,0 +1,158 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/bits"
+)
+
+// Constants of the mainnet preset.
+const (
+	slotsPerEpoch             = 32
+	maxValidatorsPerCommittee = 2048
+	// syncSubcommitteeSize is SYNC_COMMITTEE_SIZE divided by
+	// SYNC_COMMITTEE_SUBNET_COUNT.
+	syncSubcommitteeSize = 512 / 4
+)
+
+// Domain types of the consensus specs.
+var (
+	domainBeaconProposer              = [4]byte{0x00, 0x00, 0x00, 0x00}
+	domainBeaconAttester              = [4]byte{0x01, 0x00, 0x00, 0x00}
+	domainRandao                      = [4]byte{0x02, 0x00, 0x00, 0x00}
+	domainVoluntaryExit               = [4]byte{0x04, 0x00, 0x00, 0x00}
+	domainSelectionProof              = [4]byte{0x05, 0x00, 0x00, 0x00}
+	domainAggregateAndProof           = [4]byte{0x06, 0x00, 0x00, 0x00}
+	domainSyncCommittee               = [4]byte{0x07, 0x00, 0x00, 0x00}
+	domainSyncCommitteeSelectionProof = [4]byte{0x08, 0x00, 0x00, 0x00}
+	domainContributionAndProof        = [4]byte{0x09, 0x00, 0x00, 0x00}
+)
+
+func hashPair(a [32]byte, b [32]byte) [32]byte {
+	h := sha256.New()
+	h.Write(a[:])
+	h.Write(b[:])
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// merkleize computes the SSZ Merkle root of chunks, padding them with
+// zero chunks to a power of two.
+func merkleize(chunks ...[32]byte) [32]byte {
+	n := 1
+	for n < len(chunks) {
+		n *= 2
+	}
+	layer := make([][32]byte, n)
+	copy(layer, chunks)
+	for len(layer) > 1 {
+		for i := 0; i < len(layer)/2; i++ {
+			layer[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = layer[:len(layer)/2]
+	}
+	return layer[0]
+}
+
+// uint64Root is the SSZ hash tree root of a uint64.
+func uint64Root(v uint64) [32]byte {
+	var out [32]byte
+	binary.LittleEndian.PutUint64(out[:8], v)
+	return out
+}
+
+// pack splits b into 32 byte chunks, padding the last one with zeros,
+// and pads the chunks with zero chunks to at least limit chunks.
+func pack(b []byte, limit int) [][32]byte {
+	n := (len(b) + 31) / 32
+	if n < limit {
+		n = limit
+	}
+	chunks := make([][32]byte, n)
+	for i := range chunks {
+		if 32*i < len(b) {
+			copy(chunks[i][:], b[32*i:])
+		}
+	}
+	return chunks
+}
+
+func (s *signature) hashTreeRoot() [32]byte {
+	return merkleize(pack(s[:], 0)...)
+}
+
+// bitlistLen returns the number of bits of an SSZ bitlist whose last
+// byte is not zero.
+func bitlistLen(b []byte) int {
+	return 8*(len(b)-1) + bits.Len8(b[len(b)-1]) - 1
+}
+
+func (a attestationBits) hashTreeRoot() [32]byte {
+	n := bitlistLen(a)
+	b := make([]byte, (n+7)/8)
+	copy(b, a)
+	if n%8 == 0 {
+		b = b[:n/8]
+	} else {
+		b[len(b)-1] &^= 1 << uint(n%8)
+	}
+	return hashPair(merkleize(pack(b, (maxValidatorsPerCommittee+255)/256)...), uint64Root(uint64(n)))
+}
+
+func (s *syncCommitteeBits) hashTreeRoot() [32]byte {
+	return merkleize(pack(s[:], 0)...)
+}
+
+func (c *checkpoint) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(c.Epoch), c.Root)
+}
+
+func (h *beaconBlockHeader) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(h.Slot), uint64Root(h.ProposerIndex), h.ParentRoot, h.StateRoot, h.BodyRoot)
+}
+
+func (a *attestationData) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(a.Slot), uint64Root(a.Index), a.BeaconBlockRoot, a.Source.hashTreeRoot(), a.Target.hashTreeRoot())
+}
+
+func (a *attestation) hashTreeRoot() [32]byte {
+	return merkleize(a.AggregationBits.hashTreeRoot(), a.Data.hashTreeRoot(), a.Signature.hashTreeRoot())
+}
+
+func (a *aggregateAndProof) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(a.AggregatorIndex), a.Aggregate.hashTreeRoot(), a.SelectionProof.hashTreeRoot())
+}
+
+func (d *syncAggregatorSelectionData) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(d.Slot), uint64Root(d.SubcommitteeIndex))
+}
+
+func (c *syncCommitteeContribution) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(c.Slot), c.BeaconBlockRoot, uint64Root(c.SubcommitteeIndex), c.AggregationBits.hashTreeRoot(), c.Signature.hashTreeRoot())
+}
+
+func (c *contributionAndProof) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(c.AggregatorIndex), c.Contribution.hashTreeRoot(), c.SelectionProof.hashTreeRoot())
+}
+
+func (e *voluntaryExit) hashTreeRoot() [32]byte {
+	return merkleize(uint64Root(e.Epoch), uint64Root(e.ValidatorIndex))
+}
+
+// computeDomain implements compute_domain of the consensus specs.
+func computeDomain(domainType [4]byte, forkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	var version [32]byte
+	copy(version[:], forkVersion[:])
+	forkDataRoot := merkleize(version, genesisValidatorsRoot)
+	var domain [32]byte
+	copy(domain[:4], domainType[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return domain
+}
+
+// computeSigningRoot implements compute_signing_root of the consensus
+// specs.
+func computeSigningRoot(objectRoot [32]byte, domain [32]byte) [32]byte {
+	return merkleize(objectRoot, domain)
+}
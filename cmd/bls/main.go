@@ -0,0 +1,261 @@
+// Command bls creates keys and signs and verifies messages from the
+// command line.
+//
+//	bls keygen -out key.hex
+//	bls pubkey @key.hex
+//	bls sign -msg-file message.txt @key.hex
+//	bls verify -msg-file message.txt <pubkey> <signature>
+//
+// sign and verify require the message, given either as hex with -msg or
+// as a file with -msg-file, where - reads it from stdin.
+//
+// Keys, signatures and points are read and written as hex, optionally
+// 0x-prefixed. An argument of the form @path is read from a file. With
+// -group g1 (the default) public keys are in G1 and signatures in G2;
+// -group g2 swaps them.
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/phoreproject/bls"
+)
+
+// errUsage is returned when the command line is invalid. The flag set has
+// already printed the reason.
+var errUsage = errors.New("usage error")
+
+type command struct {
+	args  string
+	help  string
+	nargs int // -1 for one or more
+	run   func(c *context, args [][]byte) error
+}
+
+// context is the state shared by the subcommands.
+type context struct {
+	scheme scheme
+	out    io.Writer
+	msg    func() ([]byte, error)
+}
+
+var commands = map[string]command{
+	"keygen": {"", "generate a secret key", 0, func(c *context, _ [][]byte) error {
+		sk, err := c.scheme.keygen(rand.Reader)
+		if err != nil {
+			return err
+		}
+		return c.writeHex(sk)
+	}},
+	"pubkey": {"<secret key>", "print the public key of a secret key", 1, func(c *context, args [][]byte) error {
+		return c.writeResult(c.scheme.pubkey(args[0]))
+	}},
+	"sign": {"<secret key>", "sign a message", 1, func(c *context, args [][]byte) error {
+		msg, err := c.msg()
+		if err != nil {
+			return err
+		}
+		return c.writeResult(c.scheme.sign(args[0], msg))
+	}},
+	"verify": {"<public key> <signature>", "verify the signature of a message", 2, func(c *context, args [][]byte) error {
+		msg, err := c.msg()
+		if err != nil {
+			return err
+		}
+		return c.writeValid(c.scheme.verify(args[0], msg, args[1]))
+	}},
+	"aggregate-sigs": {"<signature>...", "aggregate signatures", -1, func(c *context, args [][]byte) error {
+		return c.writeResult(c.scheme.aggregateSigs(args))
+	}},
+	"aggregate-pubkeys": {"<public key>...", "aggregate public keys", -1, func(c *context, args [][]byte) error {
+		return c.writeResult(c.scheme.aggregatePubkeys(args))
+	}},
+	"pop-prove": {"<secret key>", "prove possession of a secret key", 1, func(c *context, args [][]byte) error {
+		return c.writeResult(c.scheme.popProve(args[0]))
+	}},
+	"pop-verify": {"<public key> <proof>", "verify a proof of possession", 2, func(c *context, args [][]byte) error {
+		return c.writeValid(c.scheme.popVerify(args[0], args[1]))
+	}},
+	"decompress": {"<public key or signature>", "print the uncompressed form of a point", 1, func(c *context, args [][]byte) error {
+		_, b, err := c.scheme.decompress(args[0])
+		return c.writeResult(b, err)
+	}},
+}
+
+// commandOrder is the order in which commands are listed in the usage.
+var commandOrder = []string{
+	"keygen", "pubkey", "sign", "verify", "aggregate-sigs", "aggregate-pubkeys",
+	"pop-prove", "pop-verify", "decompress",
+}
+
+func (c *context) writeHex(b []byte) error {
+	_, err := fmt.Fprintf(c.out, "%s\n", bls.EncodeHex(b))
+	return err
+}
+
+func (c *context) writeResult(b []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	return c.writeHex(b)
+}
+
+// writeValid prints whether a verification succeeded. It returns the
+// verification error so that the exit status reflects it.
+func (c *context) writeValid(err error) error {
+	if err != nil {
+		fmt.Fprintln(c.out, "invalid")
+		return err
+	}
+	_, err = fmt.Fprintln(c.out, "valid")
+	return err
+}
+
+// readArg reads a hex argument, or the hex contents of a file if the
+// argument starts with @.
+func readArg(arg string) ([]byte, error) {
+	text := arg
+	if strings.HasPrefix(arg, "@") {
+		b, err := ioutil.ReadFile(arg[1:])
+		if err != nil {
+			return nil, err
+		}
+		text = string(b)
+	}
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		text = text[2:]
+	}
+	b, err := hex.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", arg, bls.ErrInvalidEncoding, err)
+	}
+	return b, nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: bls <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, name := range commandOrder {
+		fmt.Fprintf(w, "  %-18s %s\n", name, commands[name].help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `run "bls <command> -h" for the flags of a command`)
+}
+
+// run runs the command line args, writing results to stdout and
+// diagnostics to stderr.
+func run(args []string, stdout io.Writer, stderr io.Writer) error {
+	if len(args) == 0 {
+		usage(stderr)
+		return errUsage
+	}
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		if name == "-h" || name == "-help" || name == "help" {
+			usage(stdout)
+			return nil
+		}
+		fmt.Fprintf(stderr, "bls: unknown command %q\n", name)
+		usage(stderr)
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet("bls "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: bls %s [flags] %s\n\n%s\n\nflags:\n", name, cmd.args, cmd.help)
+		fs.PrintDefaults()
+	}
+	group := fs.String("group", "g1", "group of the public keys, g1 or g2")
+	outPath := fs.String("out", "", "write the output to a file instead of stdout")
+	var msgHex, msgFile *string
+	if name == "sign" || name == "verify" {
+		msgHex = fs.String("msg", "", "message as hex")
+		msgFile = fs.String("msg-file", "", "file containing the raw message, - for stdin")
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return errUsage
+	}
+
+	s, ok := schemes[*group]
+	if !ok {
+		fmt.Fprintf(stderr, "bls %s: unknown group %q\n", name, *group)
+		return errUsage
+	}
+	if (cmd.nargs >= 0 && fs.NArg() != cmd.nargs) || (cmd.nargs < 0 && fs.NArg() == 0) {
+		fs.Usage()
+		return errUsage
+	}
+	values := make([][]byte, fs.NArg())
+	for i, arg := range fs.Args() {
+		b, err := readArg(arg)
+		if err != nil {
+			return err
+		}
+		values[i] = b
+	}
+
+	c := &context{scheme: s, out: stdout}
+	if msgHex != nil {
+		// The message is required, so that a forgotten flag does not
+		// silently sign or verify the empty message.
+		set := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		if set["msg"] == set["msg-file"] {
+			fmt.Fprintf(stderr, "bls %s: exactly one of -msg and -msg-file is required\n", name)
+			fs.Usage()
+			return errUsage
+		}
+		c.msg = func() ([]byte, error) {
+			switch {
+			case *msgFile == "-":
+				return ioutil.ReadAll(os.Stdin)
+			case *msgFile != "":
+				return ioutil.ReadFile(*msgFile)
+			}
+			text := strings.TrimPrefix(strings.TrimPrefix(*msgHex, "0x"), "0X")
+			b, err := hex.DecodeString(text)
+			if err != nil {
+				return nil, fmt.Errorf("-msg: %w: %v", bls.ErrInvalidEncoding, err)
+			}
+			return b, nil
+		}
+	}
+
+	if *outPath == "" {
+		return cmd.run(c, values)
+	}
+	var buf bytes.Buffer
+	c.out = &buf
+	err := cmd.run(c, values)
+	if err != nil {
+		io.Copy(stdout, &buf)
+		return err
+	}
+	// The output may be a secret key, so only the owner can read it.
+	return ioutil.WriteFile(*outPath, buf.Bytes(), 0600)
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintf(os.Stderr, "bls: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
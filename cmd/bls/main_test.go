@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runBLS(t *testing.T, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(args, &stdout, &stderr)
+	return strings.TrimSpace(stdout.String()), err
+}
+
+func mustRun(t *testing.T, args ...string) string {
+	out, err := runBLS(t, args...)
+	if err != nil {
+		t.Fatalf("bls %s: %v", strings.Join(args, " "), err)
+	}
+	return out
+}
+
+func TestCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	msgFile := filepath.Join(dir, "message")
+	if err := ioutil.WriteFile(msgFile, []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range []string{"g1", "g2"} {
+		keyFile := filepath.Join(dir, group+".key")
+		mustRun(t, "keygen", "-group", group, "-out", keyFile)
+		info, err := os.Stat(keyFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("%s: key file has mode %v", group, info.Mode().Perm())
+		}
+		key2 := mustRun(t, "keygen", "-group", group)
+
+		pub1 := mustRun(t, "pubkey", "-group", group, "@"+keyFile)
+		pub2 := mustRun(t, "pubkey", "-group", group, key2)
+
+		sig1 := mustRun(t, "sign", "-group", group, "-msg-file", msgFile, "@"+keyFile)
+		sig2 := mustRun(t, "sign", "-group", group, "-msg", "0x68656c6c6f", key2)
+		if out := mustRun(t, "verify", "-group", group, "-msg-file", msgFile, pub1, sig1); out != "valid" {
+			t.Fatalf("%s: unexpected verify output %q", group, out)
+		}
+		if out, err := runBLS(t, "verify", "-group", group, "-msg", "00", pub1, sig1); err == nil || out != "invalid" {
+			t.Fatalf("%s: signature of a different message verified: %q", group, out)
+		}
+		if _, err := runBLS(t, "verify", "-group", group, "-msg-file", msgFile, pub2, sig1); err == nil {
+			t.Fatalf("%s: signature verified with the wrong key", group)
+		}
+
+		aggSig := mustRun(t, "aggregate-sigs", "-group", group, sig1, sig2)
+		aggPub := mustRun(t, "aggregate-pubkeys", "-group", group, pub1, pub2)
+		if out := mustRun(t, "verify", "-group", group, "-msg-file", msgFile, aggPub, aggSig); out != "valid" {
+			t.Fatalf("%s: aggregate signature did not verify", group)
+		}
+
+		proof := mustRun(t, "pop-prove", "-group", group, key2)
+		if out := mustRun(t, "pop-verify", "-group", group, pub2, proof); out != "valid" {
+			t.Fatalf("%s: proof of possession did not verify", group)
+		}
+		if _, err := runBLS(t, "pop-verify", "-group", group, pub1, proof); err == nil {
+			t.Fatalf("%s: proof of possession verified for the wrong key", group)
+		}
+		if _, err := runBLS(t, "pop-verify", "-group", group, pub2, sig2); err == nil {
+			t.Fatalf("%s: message signature accepted as a proof of possession", group)
+		}
+
+		pubLen, sigLen := len(pub1), len(sig1)
+		if uncompressed := mustRun(t, "decompress", "-group", group, pub1); len(uncompressed) != 2*pubLen-2 {
+			t.Fatalf("%s: unexpected uncompressed public key length %d", group, len(uncompressed))
+		}
+		if uncompressed := mustRun(t, "decompress", "-group", group, sig1); len(uncompressed) != 2*sigLen-2 {
+			t.Fatalf("%s: unexpected uncompressed signature length %d", group, len(uncompressed))
+		}
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	key := mustRun(t, "keygen")
+	for _, args := range [][]string{
+		nil,
+		{"frobnicate"},
+		{"keygen", "-group", "g3"},
+		{"keygen", "extra"},
+		{"pubkey"},
+		{"pubkey", "0xzz"},
+		{"pubkey", "0x1234"},
+		{"pubkey", "@does-not-exist"},
+		{"sign", "-msg", "00", "-msg-file", "x", key},
+		{"sign", key},
+		{"verify", mustRun(t, "pubkey", key), mustRun(t, "sign", "-msg", "", key)},
+		{"aggregate-sigs"},
+		{"decompress", "0x1234"},
+	} {
+		if _, err := runBLS(t, args...); err == nil {
+			t.Fatalf("bls %s did not fail", strings.Join(args, " "))
+		}
+	}
+}
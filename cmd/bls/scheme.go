@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/phoreproject/bls/g1pubs"
+	"github.com/phoreproject/bls/g2pubs"
+)
+
+// scheme is one flavour of BLS signatures. All keys, signatures and
+// points are in compressed form unless noted.
+type scheme interface {
+	keygen(r io.Reader) ([]byte, error)
+	pubkey(sk []byte) ([]byte, error)
+	sign(sk []byte, msg []byte) ([]byte, error)
+	verify(pk []byte, msg []byte, sig []byte) error
+	aggregateSigs(sigs [][]byte) ([]byte, error)
+	aggregatePubkeys(pks [][]byte) ([]byte, error)
+	popProve(sk []byte) ([]byte, error)
+	popVerify(pk []byte, proof []byte) error
+
+	// decompress returns the uncompressed form of a public key or
+	// signature, telling them apart by length.
+	decompress(point []byte) (string, []byte, error)
+}
+
+var schemes = map[string]scheme{
+	"g1": g1Scheme{},
+	"g2": g2Scheme{},
+}
+
+// g1Scheme has public keys in G1 and signatures in G2.
+type g1Scheme struct{}
+
+func (g1Scheme) secretKey(b []byte) (*g1pubs.SecretKey, error) {
+	sk := new(g1pubs.SecretKey)
+	return sk, sk.UnmarshalBinary(b)
+}
+
+func (g1Scheme) publicKey(b []byte) (*g1pubs.PublicKey, error) {
+	pk := new(g1pubs.PublicKey)
+	return pk, pk.UnmarshalBinary(b)
+}
+
+func (g1Scheme) signature(b []byte) (*g1pubs.Signature, error) {
+	sig := new(g1pubs.Signature)
+	return sig, sig.UnmarshalBinary(b)
+}
+
+func (g1Scheme) keygen(r io.Reader) ([]byte, error) {
+	sk, err := g1pubs.RandKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return sk.MarshalBinary()
+}
+
+func (s g1Scheme) pubkey(skBytes []byte) ([]byte, error) {
+	sk, err := s.secretKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	return g1pubs.PrivToPub(sk).MarshalBinary()
+}
+
+func (s g1Scheme) sign(skBytes []byte, msg []byte) ([]byte, error) {
+	sk, err := s.secretKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	return g1pubs.Sign(msg, sk).MarshalBinary()
+}
+
+func (s g1Scheme) verify(pkBytes []byte, msg []byte, sigBytes []byte) error {
+	pk, err := s.publicKey(pkBytes)
+	if err != nil {
+		return err
+	}
+	sig, err := s.signature(sigBytes)
+	if err != nil {
+		return err
+	}
+	return g1pubs.VerifyE(msg, pk, sig)
+}
+
+func (s g1Scheme) aggregateSigs(sigBytes [][]byte) ([]byte, error) {
+	sigs := make([]*g1pubs.Signature, len(sigBytes))
+	for i, b := range sigBytes {
+		sig, err := s.signature(b)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %v", i+1, err)
+		}
+		sigs[i] = sig
+	}
+	return g1pubs.AggregateSignatures(sigs).MarshalBinary()
+}
+
+func (s g1Scheme) aggregatePubkeys(pkBytes [][]byte) ([]byte, error) {
+	pks := make([]*g1pubs.PublicKey, len(pkBytes))
+	for i, b := range pkBytes {
+		pk, err := s.publicKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %v", i+1, err)
+		}
+		pks[i] = pk
+	}
+	return g1pubs.AggregatePublicKeys(pks).MarshalBinary()
+}
+
+func (s g1Scheme) popProve(skBytes []byte) ([]byte, error) {
+	sk, err := s.secretKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	return g1pubs.ProvePossession(sk).MarshalBinary()
+}
+
+func (s g1Scheme) popVerify(pkBytes []byte, proofBytes []byte) error {
+	pk, err := s.publicKey(pkBytes)
+	if err != nil {
+		return err
+	}
+	proof, err := s.signature(proofBytes)
+	if err != nil {
+		return err
+	}
+	return g1pubs.VerifyPossession(pk, proof)
+}
+
+func (s g1Scheme) decompress(point []byte) (string, []byte, error) {
+	switch len(point) {
+	case 48:
+		pk, err := s.publicKey(point)
+		if err != nil {
+			return "", nil, err
+		}
+		b := pk.SerializeUncompressed()
+		return "public key", b[:], nil
+	case 96:
+		sig, err := s.signature(point)
+		if err != nil {
+			return "", nil, err
+		}
+		b := sig.SerializeUncompressed()
+		return "signature", b[:], nil
+	}
+	return "", nil, fmt.Errorf("expected a 48-byte public key or a 96-byte signature, got %d bytes", len(point))
+}
+
+// g2Scheme has public keys in G2 and signatures in G1.
+type g2Scheme struct{}
+
+func (g2Scheme) secretKey(b []byte) (*g2pubs.SecretKey, error) {
+	sk := new(g2pubs.SecretKey)
+	return sk, sk.UnmarshalBinary(b)
+}
+
+func (g2Scheme) publicKey(b []byte) (*g2pubs.PublicKey, error) {
+	pk := new(g2pubs.PublicKey)
+	return pk, pk.UnmarshalBinary(b)
+}
+
+func (g2Scheme) signature(b []byte) (*g2pubs.Signature, error) {
+	sig := new(g2pubs.Signature)
+	return sig, sig.UnmarshalBinary(b)
+}
+
+func (g2Scheme) keygen(r io.Reader) ([]byte, error) {
+	sk, err := g2pubs.RandKey(r)
+	if err != nil {
+		return nil, err
+	}
+	return sk.MarshalBinary()
+}
+
+func (s g2Scheme) pubkey(skBytes []byte) ([]byte, error) {
+	sk, err := s.secretKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	return g2pubs.PrivToPub(sk).MarshalBinary()
+}
+
+func (s g2Scheme) sign(skBytes []byte, msg []byte) ([]byte, error) {
+	sk, err := s.secretKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	return g2pubs.Sign(msg, sk).MarshalBinary()
+}
+
+func (s g2Scheme) verify(pkBytes []byte, msg []byte, sigBytes []byte) error {
+	pk, err := s.publicKey(pkBytes)
+	if err != nil {
+		return err
+	}
+	sig, err := s.signature(sigBytes)
+	if err != nil {
+		return err
+	}
+	return g2pubs.VerifyE(msg, pk, sig)
+}
+
+func (s g2Scheme) aggregateSigs(sigBytes [][]byte) ([]byte, error) {
+	sigs := make([]*g2pubs.Signature, len(sigBytes))
+	for i, b := range sigBytes {
+		sig, err := s.signature(b)
+		if err != nil {
+			return nil, fmt.Errorf("signature %d: %v", i+1, err)
+		}
+		sigs[i] = sig
+	}
+	return g2pubs.AggregateSignatures(sigs).MarshalBinary()
+}
+
+func (s g2Scheme) aggregatePubkeys(pkBytes [][]byte) ([]byte, error) {
+	pks := make([]*g2pubs.PublicKey, len(pkBytes))
+	for i, b := range pkBytes {
+		pk, err := s.publicKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %v", i+1, err)
+		}
+		pks[i] = pk
+	}
+	return g2pubs.AggregatePublicKeys(pks).MarshalBinary()
+}
+
+func (s g2Scheme) popProve(skBytes []byte) ([]byte, error) {
+	sk, err := s.secretKey(skBytes)
+	if err != nil {
+		return nil, err
+	}
+	return g2pubs.ProvePossession(sk).MarshalBinary()
+}
+
+func (s g2Scheme) popVerify(pkBytes []byte, proofBytes []byte) error {
+	pk, err := s.publicKey(pkBytes)
+	if err != nil {
+		return err
+	}
+	proof, err := s.signature(proofBytes)
+	if err != nil {
+		return err
+	}
+	return g2pubs.VerifyPossession(pk, proof)
+}
+
+func (s g2Scheme) decompress(point []byte) (string, []byte, error) {
+	switch len(point) {
+	case 96:
+		pk, err := s.publicKey(point)
+		if err != nil {
+			return "", nil, err
+		}
+		b := pk.SerializeUncompressed()
+		return "public key", b[:], nil
+	case 48:
+		sig, err := s.signature(point)
+		if err != nil {
+			return "", nil, err
+		}
+		b := sig.SerializeUncompressed()
+		return "signature", b[:], nil
+	}
+	return "", nil, fmt.Errorf("expected a 96-byte public key or a 48-byte signature, got %d bytes", len(point))
+}
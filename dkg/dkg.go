@@ -0,0 +1,441 @@
+// Package dkg implements a Pedersen distributed key generation with
+// Feldman verifiable secret sharing, producing threshold shares of a
+// g1pubs secret key without a trusted dealer.
+//
+// Each participant runs a Participant through three rounds:
+//
+//  1. Deal: broadcast the Deal and send each other participant its Share
+//     privately. Pass the messages received from the others to
+//     ReceiveDeal and ReceiveShare.
+//  2. Complaints: broadcast the complaints about invalid or missing
+//     shares and pass those of the others to ReceiveComplaint.
+//  3. Justifications: broadcast the shares revealed in answer to
+//     complaints and pass those of the others to ReceiveJustification.
+//
+// Finish then returns the participant's share of the group key. Dealers
+// that broadcast no valid deal, or do not answer every complaint against
+// them with a valid share, are disqualified. The package does not send
+// messages itself; the transport must provide a reliable broadcast
+// channel and private channels between participants, and decides when a
+// round is over.
+package dkg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/g1pubs"
+)
+
+var (
+	// ErrInvalidParameters is returned when the threshold is not between
+	// one and the number of participants, or the participant indices are
+	// zero, repeated or do not include the participant itself.
+	ErrInvalidParameters = errors.New("invalid DKG parameters")
+
+	// ErrWrongPhase is returned when a message is received or a round is
+	// started out of order.
+	ErrWrongPhase = errors.New("wrong DKG phase")
+
+	// ErrUnknownParticipant is returned when a message is from or for a
+	// participant that is not in the DKG.
+	ErrUnknownParticipant = errors.New("unknown participant")
+
+	// ErrInvalidMessage is returned when a message is malformed.
+	ErrInvalidMessage = errors.New("invalid DKG message")
+
+	// ErrDuplicateMessage is returned when a participant sends the same
+	// kind of message twice.
+	ErrDuplicateMessage = errors.New("duplicate DKG message")
+
+	// ErrTooFewQualified is returned by Finish when fewer dealers than the
+	// threshold qualified.
+	ErrTooFewQualified = errors.New("too few qualified dealers")
+)
+
+// Deal is broadcast by a dealer in the first round. The commitments are
+// the public keys of the coefficients of its sharing polynomial, lowest
+// degree first.
+type Deal struct {
+	Dealer      uint32
+	Commitments []*g1pubs.PublicKey
+}
+
+// Share is sent privately from a dealer to a recipient in the first
+// round. It is the dealer's polynomial evaluated at the recipient's
+// index.
+type Share struct {
+	Dealer    uint32
+	Recipient uint32
+	Value     *g1pubs.SecretKey
+}
+
+// Complaint is broadcast in the second round by a participant that
+// received no share or an invalid share from a dealer.
+type Complaint struct {
+	Complainer uint32
+	Dealer     uint32
+}
+
+// Justification is broadcast in the third round by a dealer to answer a
+// complaint, revealing the share of the complainer.
+type Justification struct {
+	Dealer    uint32
+	Recipient uint32
+	Value     *g1pubs.SecretKey
+}
+
+// Result is the outcome of a DKG for one participant.
+type Result struct {
+	// Share is the participant's share of the group secret key.
+	Share *g1pubs.SecretKeyShare
+
+	// GroupKey is the public key of the group secret key.
+	GroupKey *g1pubs.PublicKey
+
+	// Commitments are the commitments to the joint sharing polynomial.
+	Commitments []*g1pubs.PublicKey
+
+	// Qualified are the indices of the dealers that were not
+	// disqualified, in increasing order.
+	Qualified []uint32
+}
+
+// PublicKeyShare returns the public key of the share of the participant
+// with the given index, which verifies its partial signatures.
+func (r *Result) PublicKeyShare(index uint32) *g1pubs.PublicKeyShare {
+	return &g1pubs.PublicKeyShare{Index: index, Key: evalCommitments(r.Commitments, index)}
+}
+
+type phase int
+
+const (
+	phaseDeal phase = iota
+	phaseComplaint
+	phaseJustification
+	phaseDone
+)
+
+// dealer is what a participant knows about a dealer.
+type dealer struct {
+	commitments  []*g1pubs.PublicKey
+	share        *g1pubs.SecretKey
+	complainers  map[uint32]bool
+	justified    map[uint32]bool
+	disqualified bool
+}
+
+// Participant is the state of one participant in a DKG.
+type Participant struct {
+	index     uint32
+	threshold int
+	phase     phase
+
+	coeffs      []bls.Scalar
+	commitments []*g1pubs.PublicKey
+	dealers     map[uint32]*dealer
+	order       []uint32
+}
+
+// NewParticipant creates the participant with the given index in a DKG
+// between the participants, reading its random sharing polynomial from
+// r. Any threshold of the resulting shares can sign for the group.
+func NewParticipant(index uint32, participants []uint32, threshold int, r io.Reader) (*Participant, error) {
+	if threshold < 1 || threshold > len(participants) {
+		return nil, ErrInvalidParameters
+	}
+	p := &Participant{
+		index:     index,
+		threshold: threshold,
+		dealers:   make(map[uint32]*dealer, len(participants)),
+	}
+	for _, i := range participants {
+		if i == 0 || p.dealers[i] != nil {
+			return nil, ErrInvalidParameters
+		}
+		p.dealers[i] = &dealer{complainers: map[uint32]bool{}, justified: map[uint32]bool{}}
+		p.order = append(p.order, i)
+	}
+	if p.dealers[index] == nil {
+		return nil, ErrInvalidParameters
+	}
+	sort.Slice(p.order, func(i, j int) bool { return p.order[i] < p.order[j] })
+
+	p.coeffs = make([]bls.Scalar, threshold)
+	p.commitments = make([]*g1pubs.PublicKey, threshold)
+	for i := range p.coeffs {
+		c, err := bls.RandScalar(r)
+		if err != nil {
+			return nil, err
+		}
+		p.coeffs[i] = c
+		p.commitments[i] = g1pubs.PrivToPub(g1pubs.NewSecretKeyFromScalar(c))
+	}
+	self := p.dealers[index]
+	self.commitments = p.commitments
+	self.share = p.evalShare(index)
+	return p, nil
+}
+
+// Index returns the index of the participant.
+func (p *Participant) Index() uint32 {
+	return p.index
+}
+
+func indexRepr(index uint32) *bls.FRRepr {
+	return bls.NewFRRepr(uint64(index))
+}
+
+// evalShare evaluates the participant's polynomial at an index.
+func (p *Participant) evalShare(index uint32) *g1pubs.SecretKey {
+	x := bls.ScalarReprToScalar(*indexRepr(index))
+	y := bls.ScalarZero.Copy()
+	for i := len(p.coeffs) - 1; i >= 0; i-- {
+		y.MulAssign(x)
+		y.AddAssign(p.coeffs[i])
+	}
+	return g1pubs.NewSecretKeyFromScalar(y)
+}
+
+// evalCommitments evaluates committed polynomial at an index in the
+// exponent.
+func evalCommitments(commitments []*g1pubs.PublicKey, index uint32) *g1pubs.PublicKey {
+	x := indexRepr(index)
+	acc := bls.G1ProjectiveZero.Copy()
+	for i := len(commitments) - 1; i >= 0; i-- {
+		acc = acc.MulFR(x)
+		acc.AddAssign(commitments[i].GetPoint())
+	}
+	return g1pubs.NewPublicKeyFromG1(acc.ToAffine())
+}
+
+// verifyShare checks a share against the commitments of its dealer.
+func verifyShare(commitments []*g1pubs.PublicKey, index uint32, share *g1pubs.SecretKey) bool {
+	return g1pubs.PrivToPub(share).Equal(evalCommitments(commitments, index))
+}
+
+// Deal returns the deal to broadcast and the shares to send privately to
+// each of the other participants in the first round.
+func (p *Participant) Deal() (*Deal, []*Share, error) {
+	if p.phase != phaseDeal {
+		return nil, nil, ErrWrongPhase
+	}
+	deal := &Deal{Dealer: p.index, Commitments: append([]*g1pubs.PublicKey(nil), p.commitments...)}
+	shares := make([]*Share, 0, len(p.order)-1)
+	for _, i := range p.order {
+		if i != p.index {
+			shares = append(shares, &Share{Dealer: p.index, Recipient: i, Value: p.evalShare(i)})
+		}
+	}
+	return deal, shares, nil
+}
+
+// other returns the state of another participant.
+func (p *Participant) other(index uint32) (*dealer, error) {
+	d := p.dealers[index]
+	if d == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownParticipant, index)
+	}
+	if index == p.index {
+		return nil, fmt.Errorf("%w: message from self", ErrInvalidMessage)
+	}
+	return d, nil
+}
+
+// ReceiveDeal records the deal broadcast by another participant.
+func (p *Participant) ReceiveDeal(deal *Deal) error {
+	if p.phase != phaseDeal {
+		return ErrWrongPhase
+	}
+	d, err := p.other(deal.Dealer)
+	if err != nil {
+		return err
+	}
+	if d.commitments != nil {
+		return ErrDuplicateMessage
+	}
+	if len(deal.Commitments) != p.threshold {
+		return fmt.Errorf("%w: expected %d commitments, got %d", ErrInvalidMessage, p.threshold, len(deal.Commitments))
+	}
+	for _, c := range deal.Commitments {
+		if c == nil {
+			return fmt.Errorf("%w: missing commitment", ErrInvalidMessage)
+		}
+	}
+	d.commitments = append([]*g1pubs.PublicKey(nil), deal.Commitments...)
+	return nil
+}
+
+// ReceiveShare records the share sent privately to the participant by
+// another participant. It is checked against the dealer's commitments
+// when the complaints are made.
+func (p *Participant) ReceiveShare(share *Share) error {
+	if p.phase != phaseDeal {
+		return ErrWrongPhase
+	}
+	d, err := p.other(share.Dealer)
+	if err != nil {
+		return err
+	}
+	if share.Recipient != p.index || share.Value == nil {
+		return fmt.Errorf("%w: share is not for this participant", ErrInvalidMessage)
+	}
+	if d.share != nil {
+		return ErrDuplicateMessage
+	}
+	d.share = share.Value
+	return nil
+}
+
+// Complaints ends the first round and returns the complaints to
+// broadcast about dealers that sent no share or an invalid one. Dealers
+// that broadcast no deal are disqualified.
+func (p *Participant) Complaints() ([]*Complaint, error) {
+	if p.phase != phaseDeal {
+		return nil, ErrWrongPhase
+	}
+	p.phase = phaseComplaint
+	var complaints []*Complaint
+	for _, i := range p.order {
+		d := p.dealers[i]
+		if i == p.index {
+			continue
+		}
+		if d.commitments == nil {
+			d.disqualified = true
+			continue
+		}
+		if d.share == nil || !verifyShare(d.commitments, p.index, d.share) {
+			d.share = nil
+			d.complainers[p.index] = true
+			complaints = append(complaints, &Complaint{Complainer: p.index, Dealer: i})
+		}
+	}
+	return complaints, nil
+}
+
+// ReceiveComplaint records a complaint broadcast by another participant.
+func (p *Participant) ReceiveComplaint(c *Complaint) error {
+	if p.phase != phaseComplaint {
+		return ErrWrongPhase
+	}
+	if _, err := p.other(c.Complainer); err != nil {
+		return err
+	}
+	d := p.dealers[c.Dealer]
+	if d == nil {
+		return fmt.Errorf("%w: %d", ErrUnknownParticipant, c.Dealer)
+	}
+	if c.Dealer == c.Complainer {
+		return fmt.Errorf("%w: complaint about self", ErrInvalidMessage)
+	}
+	if d.complainers[c.Complainer] {
+		return ErrDuplicateMessage
+	}
+	d.complainers[c.Complainer] = true
+	return nil
+}
+
+// Justifications ends the second round and returns the shares to
+// broadcast in answer to the complaints against the participant.
+func (p *Participant) Justifications() ([]*Justification, error) {
+	if p.phase != phaseComplaint {
+		return nil, ErrWrongPhase
+	}
+	p.phase = phaseJustification
+	self := p.dealers[p.index]
+	var justifications []*Justification
+	for _, i := range p.order {
+		if self.complainers[i] {
+			self.justified[i] = true
+			justifications = append(justifications, &Justification{Dealer: p.index, Recipient: i, Value: p.evalShare(i)})
+		}
+	}
+	return justifications, nil
+}
+
+// ReceiveJustification records a share revealed by another participant
+// in answer to a complaint. A dealer that reveals an invalid share is
+// disqualified.
+func (p *Participant) ReceiveJustification(j *Justification) error {
+	if p.phase != phaseJustification {
+		return ErrWrongPhase
+	}
+	d, err := p.other(j.Dealer)
+	if err != nil {
+		return err
+	}
+	if !d.complainers[j.Recipient] {
+		return fmt.Errorf("%w: no complaint from %d", ErrInvalidMessage, j.Recipient)
+	}
+	if d.justified[j.Recipient] {
+		return ErrDuplicateMessage
+	}
+	d.justified[j.Recipient] = true
+	if d.disqualified {
+		return nil
+	}
+	if j.Value == nil || !verifyShare(d.commitments, j.Recipient, j.Value) {
+		d.disqualified = true
+		return nil
+	}
+	if j.Recipient == p.index {
+		d.share = j.Value
+	}
+	return nil
+}
+
+// Finish ends the third round, disqualifies the dealers that did not
+// answer every complaint against them, and returns the participant's
+// share of the group key.
+func (p *Participant) Finish() (*Result, error) {
+	if p.phase != phaseJustification {
+		return nil, ErrWrongPhase
+	}
+	p.phase = phaseDone
+	defer func() {
+		for i := range p.coeffs {
+			p.coeffs[i].Zeroize()
+		}
+	}()
+
+	var qualified []uint32
+	secret := bls.ScalarZero.Copy()
+	joint := make([]*bls.G1Projective, p.threshold)
+	for i := range joint {
+		joint[i] = bls.G1ProjectiveZero.Copy()
+	}
+	for _, i := range p.order {
+		d := p.dealers[i]
+		for c := range d.complainers {
+			if !d.justified[c] {
+				d.disqualified = true
+			}
+		}
+		if d.disqualified {
+			continue
+		}
+		qualified = append(qualified, i)
+		secret.AddAssign(d.share.GetScalar())
+		for k, c := range d.commitments {
+			joint[k].AddAssign(c.GetPoint())
+		}
+	}
+	if len(qualified) < p.threshold {
+		return nil, ErrTooFewQualified
+	}
+
+	commitments := make([]*g1pubs.PublicKey, p.threshold)
+	for k, c := range joint {
+		commitments[k] = g1pubs.NewPublicKeyFromG1(c.ToAffine())
+	}
+	return &Result{
+		Share:       &g1pubs.SecretKeyShare{Index: p.index, Key: g1pubs.NewSecretKeyFromScalar(secret)},
+		GroupKey:    commitments[0].Copy(),
+		Commitments: commitments,
+		Qualified:   qualified,
+	}, nil
+}
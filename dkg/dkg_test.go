@@ -0,0 +1,329 @@
+package dkg
+
+import (
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/g1pubs"
+)
+
+// behaviour describes how a malicious participant deviates from the
+// protocol.
+type behaviour struct {
+	noDeal            bool
+	badShares         []uint32
+	noJustification   bool
+	badJustification  bool
+	falseComplaintsOn []uint32
+}
+
+func corrupt(k *g1pubs.SecretKey) *g1pubs.SecretKey {
+	s := k.GetScalar()
+	s.AddAssign(bls.ScalarOne)
+	return g1pubs.NewSecretKeyFromScalar(s)
+}
+
+func contains(indices []uint32, i uint32) bool {
+	for _, j := range indices {
+		if i == j {
+			return true
+		}
+	}
+	return false
+}
+
+// simulate runs a DKG between participants 1 to n and returns the
+// results of all participants, with the participants.
+func simulate(t *testing.T, n int, threshold int, malicious map[uint32]*behaviour) (map[uint32]*Result, map[uint32]*Participant) {
+	indices := make([]uint32, n)
+	for i := range indices {
+		indices[i] = uint32(i + 1)
+	}
+	parties := make(map[uint32]*Participant, n)
+	for _, i := range indices {
+		p, err := NewParticipant(i, indices, threshold, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parties[i] = p
+	}
+	behave := func(i uint32) *behaviour {
+		if b := malicious[i]; b != nil {
+			return b
+		}
+		return &behaviour{}
+	}
+
+	for _, i := range indices {
+		deal, shares, err := parties[i].Deal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := behave(i)
+		for _, j := range indices {
+			if j != i && !b.noDeal {
+				if err := parties[j].ReceiveDeal(deal); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		for _, s := range shares {
+			if contains(b.badShares, s.Recipient) {
+				s.Value = corrupt(s.Value)
+			}
+			if err := parties[s.Recipient].ReceiveShare(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var complaints []*Complaint
+	for _, i := range indices {
+		cs, err := parties[i].Complaints()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range behave(i).falseComplaintsOn {
+			// The complainer records its own complaints.
+			parties[i].dealers[d].complainers[i] = true
+			cs = append(cs, &Complaint{Complainer: i, Dealer: d})
+		}
+		complaints = append(complaints, cs...)
+	}
+	for _, c := range complaints {
+		for _, j := range indices {
+			if j != c.Complainer {
+				if err := parties[j].ReceiveComplaint(c); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	justifications := make(map[uint32][]*Justification, n)
+	for _, i := range indices {
+		js, err := parties[i].Justifications()
+		if err != nil {
+			t.Fatal(err)
+		}
+		justifications[i] = js
+	}
+	for _, i := range indices {
+		b := behave(i)
+		if b.noJustification {
+			continue
+		}
+		for _, jf := range justifications[i] {
+			if b.badJustification {
+				jf.Value = corrupt(jf.Value)
+			}
+			for _, j := range indices {
+				if j != i {
+					if err := parties[j].ReceiveJustification(jf); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+		}
+	}
+
+	results := make(map[uint32]*Result, n)
+	for _, i := range indices {
+		r, err := parties[i].Finish()
+		if err != nil {
+			t.Fatal(err)
+		}
+		results[i] = r
+	}
+	return results, parties
+}
+
+// checkResults checks that the honest participants agree on the outcome
+// and that their shares sign for the group key.
+func checkResults(t *testing.T, results map[uint32]*Result, honest []uint32, qualified []uint32, threshold int) {
+	first := results[honest[0]]
+	if !reflect.DeepEqual(first.Qualified, qualified) {
+		t.Fatalf("expected qualified dealers %v, got %v", qualified, first.Qualified)
+	}
+	msg := []byte("dkg")
+	var partials []*g1pubs.PartialSignature
+	var shares []*g1pubs.SecretKeyShare
+	for _, i := range honest {
+		r := results[i]
+		if !reflect.DeepEqual(r.Qualified, first.Qualified) {
+			t.Fatalf("participant %d disagrees on the qualified dealers: %v", i, r.Qualified)
+		}
+		if !r.GroupKey.Equal(first.GroupKey) {
+			t.Fatalf("participant %d disagrees on the group key", i)
+		}
+		if r.Share.Index != i {
+			t.Fatalf("participant %d has share index %d", i, r.Share.Index)
+		}
+		if !first.PublicKeyShare(i).Key.Equal(g1pubs.PrivToPub(r.Share.Key)) {
+			t.Fatalf("public key share of participant %d does not match its secret share", i)
+		}
+		partial := g1pubs.SignShare(msg, r.Share)
+		if err := g1pubs.VerifyPartialSignature(msg, first.PublicKeyShare(i), partial); err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, partial)
+		shares = append(shares, r.Share)
+	}
+
+	sig, err := g1pubs.RecoverSignature(partials, threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g1pubs.VerifyE(msg, first.GroupKey, sig); err != nil {
+		t.Fatal(err)
+	}
+	key, err := g1pubs.RecoverSecretKey(shares, threshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1pubs.PrivToPub(key).Equal(first.GroupKey) {
+		t.Fatal("recovered secret key does not match the group key")
+	}
+}
+
+func TestDKGHonest(t *testing.T) {
+	results, parties := simulate(t, 5, 3, nil)
+	all := []uint32{1, 2, 3, 4, 5}
+	checkResults(t, results, all, all, 3)
+	checkResults(t, results, []uint32{5, 1, 3}, all, 3)
+
+	for _, p := range parties {
+		if !p.coeffs[0].IsZero() {
+			t.Fatal("polynomial was not zeroized")
+		}
+	}
+}
+
+func TestDKGMalicious(t *testing.T) {
+	results, _ := simulate(t, 7, 3, map[uint32]*behaviour{
+		// 2 sends 3 a bad share but reveals the right one when 3
+		// complains, so it stays qualified.
+		2: {badShares: []uint32{3}},
+		// 4 sends bad shares and does not answer the complaints.
+		4: {badShares: []uint32{1, 5}, noJustification: true},
+		// 5 broadcasts no deal and falsely complains about 1, which
+		// answers with a valid share.
+		5: {noDeal: true, falseComplaintsOn: []uint32{1}},
+		// 6 answers a complaint with another bad share.
+		6: {badShares: []uint32{7}, badJustification: true},
+	})
+	checkResults(t, results, []uint32{1, 3, 7}, []uint32{1, 2, 3, 7}, 3)
+	checkResults(t, results, []uint32{3, 2, 7, 1}, []uint32{1, 2, 3, 7}, 3)
+}
+
+func TestDKGTooFewQualified(t *testing.T) {
+	indices := []uint32{1, 2, 3}
+	p, err := NewParticipant(1, indices, 2, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Complaints(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Justifications(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Finish(); !errors.Is(err, ErrTooFewQualified) {
+		t.Fatalf("expected ErrTooFewQualified, got %v", err)
+	}
+}
+
+func TestDKGInvalidMessages(t *testing.T) {
+	indices := []uint32{1, 2, 3}
+	for _, tc := range []struct {
+		index        uint32
+		participants []uint32
+		threshold    int
+	}{
+		{1, indices, 0},
+		{1, indices, 4},
+		{4, indices, 2},
+		{1, []uint32{1, 2, 2}, 2},
+		{0, []uint32{0, 1, 2}, 2},
+	} {
+		if _, err := NewParticipant(tc.index, tc.participants, tc.threshold, rand.Reader); !errors.Is(err, ErrInvalidParameters) {
+			t.Fatalf("expected ErrInvalidParameters for %+v, got %v", tc, err)
+		}
+	}
+
+	p1, _ := NewParticipant(1, indices, 2, rand.Reader)
+	p2, _ := NewParticipant(2, indices, 2, rand.Reader)
+	deal, shares, err := p2.Deal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.ReceiveDeal(&Deal{Dealer: 2, Commitments: deal.Commitments[:1]}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected ErrInvalidMessage for too few commitments, got %v", err)
+	}
+	if err := p1.ReceiveDeal(&Deal{Dealer: 9, Commitments: deal.Commitments}); !errors.Is(err, ErrUnknownParticipant) {
+		t.Fatalf("expected ErrUnknownParticipant, got %v", err)
+	}
+	ownDeal, _, _ := p1.Deal()
+	if err := p1.ReceiveDeal(ownDeal); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected ErrInvalidMessage for own deal, got %v", err)
+	}
+	if err := p1.ReceiveDeal(deal); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.ReceiveDeal(deal); !errors.Is(err, ErrDuplicateMessage) {
+		t.Fatalf("expected ErrDuplicateMessage, got %v", err)
+	}
+
+	var toOne, toThree *Share
+	for _, s := range shares {
+		if s.Recipient == 1 {
+			toOne = s
+		} else {
+			toThree = s
+		}
+	}
+	if err := p1.ReceiveShare(toThree); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected ErrInvalidMessage for a share for another participant, got %v", err)
+	}
+	if err := p1.ReceiveShare(toOne); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.ReceiveShare(toOne); !errors.Is(err, ErrDuplicateMessage) {
+		t.Fatalf("expected ErrDuplicateMessage, got %v", err)
+	}
+
+	if err := p1.ReceiveComplaint(&Complaint{Complainer: 2, Dealer: 3}); !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase, got %v", err)
+	}
+	complaints, err := p1.Complaints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(complaints) != 0 {
+		t.Fatalf("expected no complaints, got %d", len(complaints))
+	}
+	if _, _, err := p1.Deal(); !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase, got %v", err)
+	}
+	if err := p1.ReceiveComplaint(&Complaint{Complainer: 2, Dealer: 2}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected ErrInvalidMessage for a complaint about self, got %v", err)
+	}
+	if err := p1.ReceiveComplaint(&Complaint{Complainer: 3, Dealer: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p1.Justifications(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p1.ReceiveJustification(&Justification{Dealer: 2, Recipient: 1, Value: toOne.Value}); !errors.Is(err, ErrInvalidMessage) {
+		t.Fatalf("expected ErrInvalidMessage for a justification without a complaint, got %v", err)
+	}
+	if _, err := p1.Finish(); !errors.Is(err, ErrTooFewQualified) {
+		t.Fatalf("expected ErrTooFewQualified after 2 did not answer, got %v", err)
+	}
+	if _, err := p1.Finish(); !errors.Is(err, ErrWrongPhase) {
+		t.Fatalf("expected ErrWrongPhase, got %v", err)
+	}
+}
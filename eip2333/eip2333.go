@@ -0,0 +1,179 @@
+// Package eip2333 implements the EIP-2333 BLS12-381 key tree and the
+// EIP-2334 paths used to derive validator keys from it.
+package eip2333
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/g1pubs"
+	"golang.org/x/crypto/hkdf"
+)
+
+// EIP-2334 path components for Ethereum validator keys.
+const (
+	Purpose  = 12381
+	CoinType = 3600
+)
+
+// MinSeedLength is the minimum length of a seed for DeriveMasterSK.
+const MinSeedLength = 32
+
+var (
+	// ErrSeedTooShort is returned when a seed is shorter than
+	// MinSeedLength.
+	ErrSeedTooShort = errors.New("seed must be at least 32 bytes")
+
+	// ErrInvalidPath is returned when a derivation path cannot be parsed.
+	ErrInvalidPath = errors.New("invalid derivation path")
+)
+
+const (
+	lamportChunks    = 255
+	hkdfModROutput   = 48
+	keyGenSaltPrefix = "BLS-SIG-KEYGEN-SALT-"
+)
+
+var rModulus = bls.RFieldModulus.ToBig()
+
+// hkdfModR implements HKDF_mod_r with an empty key_info.
+func hkdfModR(ikm []byte) *g1pubs.SecretKey {
+	input := make([]byte, len(ikm)+1)
+	copy(input, ikm)
+	defer zeroBytes(input)
+
+	info := []byte{0, hkdfModROutput}
+	salt := []byte(keyGenSaltPrefix)
+	okm := make([]byte, hkdfModROutput)
+	defer zeroBytes(okm)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		if _, err := io.ReadFull(hkdf.New(sha256.New, input, salt, info), okm); err != nil {
+			panic(err)
+		}
+		sk.SetBytes(okm)
+		sk.Mod(sk, rModulus)
+	}
+	repr, err := bls.FRReprFromBigInt(sk)
+	if err != nil {
+		panic(err)
+	}
+	return g1pubs.NewSecretKeyFromScalar(bls.ScalarReprToScalar(*repr))
+}
+
+// ikmToLamportSK expands the input keying material to the 255 chunks of
+// a Lamport secret key.
+func ikmToLamportSK(ikm []byte, salt []byte) []byte {
+	okm := make([]byte, 32*lamportChunks)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, nil), okm); err != nil {
+		panic(err)
+	}
+	return okm
+}
+
+// parentSKToLamportPK computes the compressed Lamport public key used
+// as the input keying material of a child key.
+func parentSKToLamportPK(parent *g1pubs.SecretKey, index uint32) []byte {
+	salt := []byte{byte(index >> 24), byte(index >> 16), byte(index >> 8), byte(index)}
+	ikm := parent.Serialize()
+	defer zeroBytes(ikm[:])
+
+	lamport0 := ikmToLamportSK(ikm[:], salt)
+	defer zeroBytes(lamport0)
+	notIKM := ikm
+	for i := range notIKM {
+		notIKM[i] = ^notIKM[i]
+	}
+	defer zeroBytes(notIKM[:])
+	lamport1 := ikmToLamportSK(notIKM[:], salt)
+	defer zeroBytes(lamport1)
+
+	h := sha256.New()
+	for _, lamport := range [][]byte{lamport0, lamport1} {
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(lamport[32*i : 32*(i+1)])
+			h.Write(chunk[:])
+		}
+	}
+	return h.Sum(nil)
+}
+
+// DeriveMasterSK derives the master secret key from a seed of at least
+// MinSeedLength bytes.
+func DeriveMasterSK(seed []byte) (*g1pubs.SecretKey, error) {
+	if len(seed) < MinSeedLength {
+		return nil, ErrSeedTooShort
+	}
+	return hkdfModR(seed), nil
+}
+
+// DeriveChildSK derives the hardened child secret key with the given
+// index.
+func DeriveChildSK(parent *g1pubs.SecretKey, index uint32) *g1pubs.SecretKey {
+	return hkdfModR(parentSKToLamportPK(parent, index))
+}
+
+// ParsePath parses a derivation path such as m/12381/3600/0/0/0 into
+// its indices.
+func ParsePath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, fmt.Errorf("%w: %q must start with m", ErrInvalidPath, path)
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, p := range parts[1:] {
+		if p == "" || (len(p) > 1 && p[0] == '0') || strings.TrimLeft(p, "0123456789") != "" {
+			return nil, fmt.Errorf("%w: bad index %q in %q", ErrInvalidPath, p, path)
+		}
+		i, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad index %q in %q", ErrInvalidPath, p, path)
+		}
+		indices = append(indices, uint32(i))
+	}
+	return indices, nil
+}
+
+// DerivePath derives the secret key at a path from a seed.
+func DerivePath(seed []byte, path string) (*g1pubs.SecretKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := DeriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range indices {
+		child := DeriveChildSK(sk, i)
+		sk.Zeroize()
+		sk = child
+	}
+	return sk, nil
+}
+
+// WithdrawalKeyPath returns the EIP-2334 path of the withdrawal key of
+// a validator.
+func WithdrawalKeyPath(validator uint32) string {
+	return fmt.Sprintf("m/%d/%d/%d/0", Purpose, CoinType, validator)
+}
+
+// SigningKeyPath returns the EIP-2334 path of the signing key of a
+// validator.
+func SigningKeyPath(validator uint32) string {
+	return WithdrawalKeyPath(validator) + "/0"
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
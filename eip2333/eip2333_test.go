@@ -0,0 +1,137 @@
+package eip2333
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/phoreproject/bls/g1pubs"
+)
+
+// Test vectors from EIP-2333.
+var eip2333Vectors = []struct {
+	seed     string
+	masterSK string
+	index    uint32
+	childSK  string
+}{
+	{
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		masterSK: "6083874454709270928345386274498605044986640685124978867557563392430687146096",
+		index:    0,
+		childSK:  "20397789859736650942317412262472558107875392172444076792671091975210932703118",
+	},
+	{
+		seed:     "3141592653589793238462643383279502884197169399375105820974944592",
+		masterSK: "29757020647961307431480504535336562678282505419141012933316116377660817309383",
+		index:    3141592653,
+		childSK:  "25457201688850691947727629385191704516744796114925897962676248250929345014287",
+	},
+	{
+		seed:     "0099FF991111002299DD7744EE3355BBDD8844115566CC55663355668888CC00",
+		masterSK: "27580842291869792442942448775674722299803720648445448686099262467207037398656",
+		index:    4294967295,
+		childSK:  "29358610794459428860402234341874281240803786294062035874021252734817515685787",
+	},
+	{
+		seed:     "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		masterSK: "19022158461524446591288038168518313374041767046816487870552872741050760015818",
+		index:    42,
+		childSK:  "31372231650479070279774297061823572166496564838472787488249775572789064611981",
+	},
+}
+
+func secretKeyToDecimal(sk *g1pubs.SecretKey) string {
+	b := sk.Serialize()
+	return new(big.Int).SetBytes(b[:]).String()
+}
+
+func TestEIP2333Vectors(t *testing.T) {
+	for i, v := range eip2333Vectors {
+		seed, _ := hex.DecodeString(v.seed)
+		master, err := DeriveMasterSK(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := secretKeyToDecimal(master); got != v.masterSK {
+			t.Fatalf("vector %d: expected master key %s, got %s", i, v.masterSK, got)
+		}
+		child := DeriveChildSK(master, v.index)
+		if got := secretKeyToDecimal(child); got != v.childSK {
+			t.Fatalf("vector %d: expected child key %s, got %s", i, v.childSK, got)
+		}
+	}
+}
+
+func TestLamportPK(t *testing.T) {
+	seed, _ := hex.DecodeString(eip2333Vectors[0].seed)
+	master, _ := DeriveMasterSK(seed)
+	expected := "dd635d27d1d52b9a49df9e5c0c622360a4dd17cba7db4e89bce3cb048fb721a5"
+	if got := hex.EncodeToString(parentSKToLamportPK(master, 0)); got != expected {
+		t.Fatalf("expected compressed Lamport public key %s, got %s", expected, got)
+	}
+}
+
+func TestDeriveMasterSKShortSeed(t *testing.T) {
+	if _, err := DeriveMasterSK(make([]byte, 31)); !errors.Is(err, ErrSeedTooShort) {
+		t.Fatalf("expected ErrSeedTooShort, got %v", err)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	indices, err := ParsePath(SigningKeyPath(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{12381, 3600, 7, 0, 0}
+	if len(indices) != len(expected) {
+		t.Fatalf("expected %d indices, got %d", len(expected), len(indices))
+	}
+	for i := range expected {
+		if indices[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, indices)
+		}
+	}
+
+	if indices, err := ParsePath("m"); err != nil || len(indices) != 0 {
+		t.Fatal("m should parse to the master key")
+	}
+	if indices, err := ParsePath("m/4294967295"); err != nil || indices[0] != 4294967295 {
+		t.Fatal("the largest index should parse")
+	}
+
+	for _, path := range []string{"", "/0", "x/0", "m/", "m//0", "m/-1", "m/+1", "m/01", "m/0'", "m/4294967296", "m/1 "} {
+		if _, err := ParsePath(path); !errors.Is(err, ErrInvalidPath) {
+			t.Fatalf("expected ErrInvalidPath for %q, got %v", path, err)
+		}
+	}
+}
+
+func TestDerivePath(t *testing.T) {
+	seed, _ := hex.DecodeString(eip2333Vectors[0].seed)
+	sk, err := DerivePath(seed, "m/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := secretKeyToDecimal(sk); got != eip2333Vectors[0].childSK {
+		t.Fatalf("expected m/0 to be the child key from the test vector, got %s", got)
+	}
+
+	sk, err = DerivePath(seed, SigningKeyPath(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, _ := DeriveMasterSK(seed)
+	expected := master
+	for _, i := range []uint32{12381, 3600, 0, 0, 0} {
+		expected = DeriveChildSK(expected, i)
+	}
+	if sk.Serialize() != expected.Serialize() {
+		t.Fatal("DerivePath should derive each index in turn")
+	}
+
+	if _, err := DerivePath(seed, "m/a"); !errors.Is(err, ErrInvalidPath) {
+		t.Fatalf("expected ErrInvalidPath, got %v", err)
+	}
+}
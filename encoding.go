@@ -0,0 +1,114 @@
+package bls
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+// EncodeHex encodes bytes as 0x-prefixed hex.
+func EncodeHex(b []byte) []byte {
+	out := make([]byte, 2+hex.EncodedLen(len(b)))
+	out[0] = '0'
+	out[1] = 'x'
+	hex.Encode(out[2:], b)
+	return out
+}
+
+// DecodeHex decodes hex with an optional 0x prefix and checks that it
+// decodes to exactly size bytes.
+func DecodeHex(text []byte, size int) ([]byte, error) {
+	if len(text) >= 2 && text[0] == '0' && (text[1] == 'x' || text[1] == 'X') {
+		text = text[2:]
+	}
+	if len(text) != hex.EncodedLen(size) {
+		return nil, fmt.Errorf("%w: expected %d hex characters, got %d", ErrInvalidEncoding, hex.EncodedLen(size), len(text))
+	}
+	out := make([]byte, size)
+	if _, err := hex.Decode(out, text); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	return out, nil
+}
+
+func checkLength(data []byte, size int) error {
+	if len(data) != size {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidEncoding, size, len(data))
+	}
+	return nil
+}
+
+// MarshalBinary encodes the point in compressed form.
+func (g G1Affine) MarshalBinary() ([]byte, error) {
+	b := CompressG1(&g)
+	return b[:], nil
+}
+
+// UnmarshalBinary decodes a compressed point and checks that it is
+// in the correct subgroup.
+func (g *G1Affine) UnmarshalBinary(data []byte) error {
+	if err := checkLength(data, 48); err != nil {
+		return err
+	}
+	var b [48]byte
+	copy(b[:], data)
+	a, err := DecompressG1(b)
+	if err != nil {
+		return err
+	}
+	*g = *a
+	return nil
+}
+
+// MarshalText encodes the compressed point as 0x-prefixed hex.
+func (g G1Affine) MarshalText() ([]byte, error) {
+	b := CompressG1(&g)
+	return EncodeHex(b[:]), nil
+}
+
+// UnmarshalText decodes a hex compressed point and checks that it is
+// in the correct subgroup.
+func (g *G1Affine) UnmarshalText(text []byte) error {
+	data, err := DecodeHex(text, 48)
+	if err != nil {
+		return err
+	}
+	return g.UnmarshalBinary(data)
+}
+
+// MarshalBinary encodes the point in compressed form.
+func (g G2Affine) MarshalBinary() ([]byte, error) {
+	b := CompressG2(&g)
+	return b[:], nil
+}
+
+// UnmarshalBinary decodes a compressed point and checks that it is
+// in the correct subgroup.
+func (g *G2Affine) UnmarshalBinary(data []byte) error {
+	if err := checkLength(data, 96); err != nil {
+		return err
+	}
+	var b [96]byte
+	copy(b[:], data)
+	a, err := DecompressG2(b)
+	if err != nil {
+		return err
+	}
+	*g = *a
+	return nil
+}
+
+// MarshalText encodes the compressed point as 0x-prefixed hex.
+func (g G2Affine) MarshalText() ([]byte, error) {
+	b := CompressG2(&g)
+	return EncodeHex(b[:]), nil
+}
+
+// UnmarshalText decodes a hex compressed point and checks that it is
+// in the correct subgroup.
+func (g *G2Affine) UnmarshalText(text []byte) error {
+	data, err := DecodeHex(text, 96)
+	if err != nil {
+		return err
+	}
+	return g.UnmarshalBinary(data)
+}
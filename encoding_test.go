@@ -0,0 +1,124 @@
+package bls_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+func TestG1AffineMarshalRoundTrip(t *testing.T) {
+	r := NewXORShift(1)
+	p, _ := bls.RandG1(r)
+	a := p.ToAffine()
+
+	b, err := a.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary bls.G1Affine
+	if err := fromBinary.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if !fromBinary.Equals(a) {
+		t.Fatal("point did not round trip through binary encoding")
+	}
+
+	text, err := a.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(text), "0x") || len(text) != 2+96 {
+		t.Fatalf("unexpected text encoding %s", text)
+	}
+	var fromText bls.G1Affine
+	if err := fromText.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	if !fromText.Equals(a) {
+		t.Fatal("point did not round trip through text encoding")
+	}
+
+	type wrapper struct {
+		Point *bls.G1Affine `json:"point"`
+	}
+	j, err := json.Marshal(wrapper{a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(j) != `{"point":"`+string(text)+`"}` {
+		t.Fatalf("unexpected JSON encoding %s", j)
+	}
+	var w wrapper
+	if err := json.Unmarshal(j, &w); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Point.Equals(a) {
+		t.Fatal("point did not round trip through JSON")
+	}
+}
+
+func TestG2AffineMarshalRoundTrip(t *testing.T) {
+	r := NewXORShift(2)
+	p, _ := bls.RandG2(r)
+	a := p.ToAffine()
+
+	b, _ := a.MarshalBinary()
+	var fromBinary bls.G2Affine
+	if err := fromBinary.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if !fromBinary.Equals(a) {
+		t.Fatal("point did not round trip through binary encoding")
+	}
+
+	j, err := json.Marshal([]bls.G2Affine{*a})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var points []bls.G2Affine
+	if err := json.Unmarshal(j, &points); err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1 || !points[0].Equals(a) {
+		t.Fatal("point did not round trip through JSON")
+	}
+}
+
+func TestAffineUnmarshalInvalid(t *testing.T) {
+	var g1 bls.G1Affine
+	if err := g1.UnmarshalBinary(make([]byte, 47)); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for short input, got %v", err)
+	}
+	if err := g1.UnmarshalText([]byte("0xzz")); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for bad hex, got %v", err)
+	}
+
+	// x = 0 gives a point of order 3
+	notInSubgroup := make([]byte, 48)
+	notInSubgroup[0] = 0x80
+	if err := g1.UnmarshalBinary(notInSubgroup); !errors.Is(err, bls.ErrNotInSubgroup) {
+		t.Fatalf("expected ErrNotInSubgroup, got %v", err)
+	}
+
+	var g2 bls.G2Affine
+	if err := json.Unmarshal([]byte(`"0x00"`), &g2); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for short JSON string, got %v", err)
+	}
+}
+
+func TestDecodeHexPrefix(t *testing.T) {
+	withPrefix, err := bls.DecodeHex([]byte("0x0102"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withoutPrefix, err := bls.DecodeHex([]byte("0102"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(withPrefix) != "\x01\x02" || string(withoutPrefix) != "\x01\x02" {
+		t.Fatal("unexpected hex decoding")
+	}
+}
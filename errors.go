@@ -0,0 +1,109 @@
+package bls
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidEncoding is returned when bytes are not a valid encoding of
+	// a point or field element. More specific encoding errors wrap it.
+	ErrInvalidEncoding = errors.New("invalid encoding")
+
+	// ErrNotOnCurve is returned when a point does not satisfy the curve
+	// equation.
+	ErrNotOnCurve = errors.New("point not on curve")
+
+	// ErrNotInSubgroup is returned when an element is not in the prime
+	// order subgroup.
+	ErrNotInSubgroup = errors.New("not in correct subgroup")
+
+	// ErrIdentityKey is returned when a public key is the point at
+	// infinity.
+	ErrIdentityKey = errors.New("public key is the identity")
+
+	// ErrIdentitySignature is returned when a signature is the point at
+	// infinity.
+	ErrIdentitySignature = errors.New("signature is the identity")
+
+	// ErrMessageCountMismatch is returned when the number of public keys
+	// and messages given to an aggregate verification differ.
+	ErrMessageCountMismatch = errors.New("number of public keys and messages do not match")
+
+	// ErrDuplicateMessage is returned when an aggregate verification is
+	// given the same message more than once.
+	ErrDuplicateMessage = errors.New("duplicate message in aggregate")
+
+	// ErrInvalidSignature is returned when a well-formed signature does not
+	// verify.
+	ErrInvalidSignature = errors.New("signature does not verify")
+
+	// ErrZeroSecretKey is returned when a secret key is zero.
+	ErrZeroSecretKey = errors.New("secret key is zero")
+
+	// ErrDSTTooLong is returned when a domain separation tag is longer
+	// than MaxDSTLength.
+	ErrDSTTooLong = errors.New("domain separation tag is too long")
+
+	// ErrUnsupportedSignerOpts is returned when a signer is given options
+	// that request prehashing or are of an unknown type.
+	ErrUnsupportedSignerOpts = errors.New("unsupported signer options")
+
+	// ErrInvalidThreshold is returned when a threshold is not between one
+	// and the number of shares.
+	ErrInvalidThreshold = errors.New("invalid threshold")
+
+	// ErrNotEnoughShares is returned when fewer shares than the threshold
+	// are given.
+	ErrNotEnoughShares = errors.New("not enough shares")
+
+	// ErrInvalidShareIndex is returned when a share index is zero or does
+	// not match the index of the share it is checked against.
+	ErrInvalidShareIndex = errors.New("invalid share index")
+
+	// ErrDuplicateShareIndex is returned when two shares have the same
+	// index.
+	ErrDuplicateShareIndex = errors.New("duplicate share index")
+
+	// ErrSignatureCountMismatch is returned when the number of signatures
+	// and public keys given to an aggregation differ.
+	ErrSignatureCountMismatch = errors.New("number of signatures and public keys do not match")
+
+	// ErrNotTorusCompressible is returned when a GT element has no T6
+	// torus compression.
+	ErrNotTorusCompressible = errors.New("element cannot be torus compressed")
+)
+
+var (
+	// ErrUnexpectedCompressionMode is returned when the compression flag of
+	// an encoded point does not match the expected encoding.
+	ErrUnexpectedCompressionMode = fmt.Errorf("%w: unexpected compression mode", ErrInvalidEncoding)
+
+	// ErrInfinitySortFlag is returned when an encoded point at infinity
+	// has the sort flag set.
+	ErrInfinitySortFlag = fmt.Errorf("%w: sort flag set on point at infinity", ErrInvalidEncoding)
+
+	// ErrInfinityNotZero is returned when an encoded point at infinity has
+	// non-zero coordinate bytes.
+	ErrInfinityNotZero = fmt.Errorf("%w: unexpected information in encoded infinity", ErrInvalidEncoding)
+
+	// ErrUnexpectedSortFlag is returned when an uncompressed point has the
+	// sort flag set.
+	ErrUnexpectedSortFlag = fmt.Errorf("%w: unexpected sort flag in uncompressed point", ErrInvalidEncoding)
+
+	// ErrNonCanonicalCoordinate is returned when an encoded coordinate is
+	// not less than the field modulus.
+	ErrNonCanonicalCoordinate = fmt.Errorf("%w: coordinate is not less than the field modulus", ErrInvalidEncoding)
+
+	// ErrG2LimbFlags is returned when the flag bits of the second limb of a
+	// compressed G2 point are set.
+	ErrG2LimbFlags = fmt.Errorf("%w: unexpected flag bits in second limb of G2 point", ErrInvalidEncoding)
+
+	// ErrSecretKeyOutOfRange is returned when an encoded secret key is not
+	// less than the group order.
+	ErrSecretKeyOutOfRange = fmt.Errorf("%w: secret key is not less than the group order", ErrInvalidEncoding)
+
+	// ErrUnexpectedAlgorithm is returned when an encoded key is for a
+	// different algorithm than expected.
+	ErrUnexpectedAlgorithm = fmt.Errorf("%w: unexpected key algorithm", ErrInvalidEncoding)
+)
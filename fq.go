@@ -347,3 +347,18 @@ func RandFQ(reader io.Reader) (FQ, error) {
 	f := FQReprToFQ(b)
 	return f, nil
 }
+
+func putFQ(out []byte, f FQ) {
+	b := f.ToRepr().Bytes()
+	copy(out, b[:])
+}
+
+func getFQ(in []byte) (FQ, error) {
+	var b [48]byte
+	copy(b[:], in)
+	repr := FQReprFromBytes(b)
+	if repr.Cmp(QFieldModulus) >= 0 {
+		return FQ{}, ErrNonCanonicalCoordinate
+	}
+	return FQReprToFQ(repr), nil
+}
@@ -235,3 +235,69 @@ func (f *FQ12) InverseAssign() bool {
 
 	return true
 }
+
+// fp4Square squares a + b*s in Fq4 = Fq2[s]/(s^2 - (1 + u)).
+func fp4Square(a FQ2, b FQ2) (FQ2, FQ2) {
+	t0 := a.Copy()
+	t0.SquareAssign()
+	t1 := b.Copy()
+	t1.SquareAssign()
+	c0 := t1.Copy()
+	c0.MultiplyByNonresidueAssign()
+	c0.AddAssign(t0)
+	c1 := a.Copy()
+	c1.AddAssign(b)
+	c1.SquareAssign()
+	c1.SubAssign(t0)
+	c1.SubAssign(t1)
+	return c0, c1
+}
+
+// CyclotomicSquareAssign squares an element of the cyclotomic
+// subgroup. The result is undefined for other elements.
+func (f *FQ12) CyclotomicSquareAssign() {
+	// Granger-Scott squaring
+	// https://eprint.iacr.org/2009/565.pdf (section 3.2)
+	z0 := f.c0.c0
+	z4 := f.c0.c1
+	z3 := f.c0.c2
+	z2 := f.c1.c0
+	z1 := f.c1.c1
+	z5 := f.c1.c2
+
+	t0, t1 := fp4Square(z0, z1)
+
+	z0.SubAssign(t0)
+	z0.NegAssign()
+	z0.DoubleAssign()
+	z0.AddAssign(t0)
+
+	z1.AddAssign(t1)
+	z1.DoubleAssign()
+	z1.AddAssign(t1)
+
+	t0, t1 = fp4Square(z2, z3)
+	t2, t3 := fp4Square(z4, z5)
+
+	z4.SubAssign(t0)
+	z4.NegAssign()
+	z4.DoubleAssign()
+	z4.AddAssign(t0)
+
+	z5.AddAssign(t1)
+	z5.DoubleAssign()
+	z5.AddAssign(t1)
+
+	t3.MultiplyByNonresidueAssign()
+	z2.AddAssign(t3)
+	z2.DoubleAssign()
+	z2.AddAssign(t3)
+
+	z3.SubAssign(t2)
+	z3.NegAssign()
+	z3.DoubleAssign()
+	z3.AddAssign(t2)
+
+	f.c0 = NewFQ6(z0, z4, z3)
+	f.c1 = NewFQ6(z2, z1, z5)
+}
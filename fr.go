@@ -20,17 +20,18 @@ func (f *FR) IsValid() bool {
 	return f.n.Cmp(RFieldModulus) < 0
 }
 
-func (f *FR) reduceAssign() {
-	if !f.IsValid() {
-		f.n.SubNoBorrow(RFieldModulus)
-	}
-}
-
 // Copy copies an FR element.
 func (f *FR) Copy() *FR {
 	return &FR{f.n.Copy()}
 }
 
+// Zeroize overwrites the limbs of the element with zeros.
+func (f *FR) Zeroize() {
+	for i := range f.n {
+		f.n[i] = 0
+	}
+}
+
 // FRR is 2**256 % r used for moving numbers into Montgomery form.
 var FRR, _ = FRReprFromString("10920338887063814464675503992315976177888879664585288394250266608035967270910", 10)
 
@@ -50,78 +51,17 @@ func FRReprToFR(o *FRRepr) *FR {
 
 // AddAssign multiplies a field element by this one.
 func (f *FR) AddAssign(other *FR) {
-	f.n.AddNoCarry(other.n)
-	f.reduceAssign()
-}
-
-const montInvFR = uint64(0xfffffffeffffffff)
-
-func (f *FR) montReduce(r0 uint64, r1 uint64, r2 uint64, r3 uint64, r4 uint64, r5 uint64, r6 uint64, r7 uint64) {
-	k := r0 * montInvFR
-	_, carry := MACWithCarry(r0, k, RFieldModulus[0], 0)
-	r1, carry = MACWithCarry(r1, k, RFieldModulus[1], carry)
-	r2, carry = MACWithCarry(r2, k, RFieldModulus[2], carry)
-	r3, carry = MACWithCarry(r3, k, RFieldModulus[3], carry)
-	r4, carry = AddWithCarry(r4, 0, carry)
-	carry2 := carry
-	k = r1 * montInvFR
-	_, carry = MACWithCarry(r1, k, RFieldModulus[0], 0)
-	r2, carry = MACWithCarry(r2, k, RFieldModulus[1], carry)
-	r3, carry = MACWithCarry(r3, k, RFieldModulus[2], carry)
-	r4, carry = MACWithCarry(r4, k, RFieldModulus[3], carry)
-	r5, carry = AddWithCarry(r5, carry2, carry)
-	carry2 = carry
-	k = r2 * montInvFR
-	_, carry = MACWithCarry(r2, k, RFieldModulus[0], 0)
-	r3, carry = MACWithCarry(r3, k, RFieldModulus[1], carry)
-	r4, carry = MACWithCarry(r4, k, RFieldModulus[2], carry)
-	r5, carry = MACWithCarry(r5, k, RFieldModulus[3], carry)
-	r6, carry = AddWithCarry(r6, carry2, carry)
-	carry2 = carry
-	k = r3 * montInvFR
-	_, carry = MACWithCarry(r3, k, RFieldModulus[0], 0)
-	r4, carry = MACWithCarry(r4, k, RFieldModulus[1], carry)
-	r5, carry = MACWithCarry(r5, k, RFieldModulus[2], carry)
-	r6, carry = MACWithCarry(r6, k, RFieldModulus[3], carry)
-	r7, _ = AddWithCarry(r7, carry2, carry)
-	f.n[0] = r4
-	f.n[1] = r5
-	f.n[2] = r6
-	f.n[3] = r7
-	f.reduceAssign()
+	*f.n = AddFR(*f.n, *other.n)
 }
 
 // MulAssign multiplies a field element by this one.
 func (f FR) MulAssign(other *FR) {
-	r0, carry := MACWithCarry(0, f.n[0], other.n[0], 0)
-	r1, carry := MACWithCarry(0, f.n[0], other.n[1], carry)
-	r2, carry := MACWithCarry(0, f.n[0], other.n[2], carry)
-	r3, carry := MACWithCarry(0, f.n[0], other.n[3], carry)
-	r4 := carry
-	r1, carry = MACWithCarry(r1, f.n[1], other.n[0], 0)
-	r2, carry = MACWithCarry(r2, f.n[1], other.n[1], carry)
-	r3, carry = MACWithCarry(r3, f.n[1], other.n[2], carry)
-	r4, carry = MACWithCarry(r4, f.n[1], other.n[3], carry)
-	r5 := carry
-	r2, carry = MACWithCarry(r2, f.n[2], other.n[0], 0)
-	r3, carry = MACWithCarry(r3, f.n[2], other.n[1], carry)
-	r4, carry = MACWithCarry(r4, f.n[2], other.n[2], carry)
-	r5, carry = MACWithCarry(r5, f.n[2], other.n[3], carry)
-	r6 := carry
-	r3, carry = MACWithCarry(r3, f.n[3], other.n[0], 0)
-	r4, carry = MACWithCarry(r4, f.n[3], other.n[1], carry)
-	r5, carry = MACWithCarry(r5, f.n[3], other.n[2], carry)
-	r6, carry = MACWithCarry(r6, f.n[3], other.n[3], carry)
-	r7 := carry
-	f.montReduce(r0, r1, r2, r3, r4, r5, r6, r7)
+	*f.n = MultiplyFR(*f.n, *other.n)
 }
 
 // SubAssign subtracts a field element from this one.
 func (f *FR) SubAssign(other *FR) {
-	if other.n.Cmp(f.n) > 0 {
-		f.n.AddNoCarry(RFieldModulus)
-	}
-	f.n.SubNoBorrow(other.n)
+	*f.n = SubFR(*f.n, *other.n)
 }
 
 var frOne = NewFRRepr(1)
@@ -169,8 +109,7 @@ func (f FR) Cmp(other *FR) int {
 
 // DoubleAssign doubles the element
 func (f *FR) DoubleAssign() {
-	f.n.Mul2()
-	f.reduceAssign()
+	*f.n = AddFR(*f.n, *f.n)
 }
 
 // IsZero checks if the field element is zero.
@@ -180,39 +119,21 @@ func (f FR) IsZero() bool {
 
 // SquareAssign squares a field element.
 func (f *FR) SquareAssign() {
-	r1, carry := MACWithCarry(0, f.n[0], f.n[1], 0)
-	r2, carry := MACWithCarry(0, f.n[0], f.n[2], carry)
-	r3, carry := MACWithCarry(0, f.n[0], f.n[3], carry)
-	r4 := carry
-	r3, carry = MACWithCarry(r3, f.n[1], f.n[2], 0)
-	r4, carry = MACWithCarry(r4, f.n[1], f.n[3], carry)
-	r5 := carry
-	r5, carry = MACWithCarry(r5, f.n[2], f.n[3], 0)
-	r6 := carry
-	r7 := r6 >> 63
-	r6 = (r6 << 1) | (r5 >> 63)
-	r5 = (r5 << 1) | (r4 >> 63)
-	r4 = (r4 << 1) | (r3 >> 63)
-	r3 = (r3 << 1) | (r2 >> 63)
-	r2 = (r2 << 1) | (r1 >> 63)
-	r1 = r1 << 1
-
-	carry = 0
-	r0, carry := MACWithCarry(0, f.n[0], f.n[0], carry)
-	r1, carry = AddWithCarry(r1, 0, carry)
-	r2, carry = MACWithCarry(r2, f.n[1], f.n[1], carry)
-	r3, carry = AddWithCarry(r3, 0, carry)
-	r4, carry = MACWithCarry(r4, f.n[2], f.n[2], carry)
-	r5, carry = AddWithCarry(r5, 0, carry)
-	r6, carry = MACWithCarry(r6, f.n[3], f.n[3], carry)
-	r7, carry = AddWithCarry(r7, 0, carry)
-	f.montReduce(r0, r1, r2, r3, r4, r5, r6, r7)
+	*f.n = SquareFR(*f.n)
 }
 
 // Sqrt calculates the square root of the field element.
-func (f FR) Sqrt() *FR {
-	// TODO: fixme
-	return FRReprToFR(frOne)
+func (f FR) Sqrt() (*FR, bool) {
+	s, ok := ScalarFromFR(&f).Sqrt()
+	if !ok {
+		return nil, false
+	}
+	return s.ToFR(), true
+}
+
+// IsSquare checks if the element is a quadratic residue or zero.
+func (f *FR) IsSquare() bool {
+	return f.Legendre() != LegendreQuadraticNonResidue
 }
 
 // Inverse finds the inverse of the field element.
@@ -314,18 +235,8 @@ func (f *FR) Legendre() LegendreSymbol {
 
 // ToRepr gets the 256-bit representation of the field element.
 func (f *FR) ToRepr() *FRRepr {
-	out := f.Copy()
-	out.montReduce(
-		f.n[0],
-		f.n[1],
-		f.n[2],
-		f.n[3],
-		0,
-		0,
-		0,
-		0,
-	)
-	return out.n
+	out := FRRepr(MultiplyFR(*f.n, [4]uint64{1, 0, 0, 0}))
+	return &out
 }
 
 // Bytes gets the representation of the FR in bytes.
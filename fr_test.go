@@ -2,6 +2,7 @@ package bls
 
 import (
 	"crypto/rand"
+	"math/big"
 	"testing"
 )
 
@@ -16,3 +17,78 @@ func TestFRInverse(t *testing.T) {
 		}
 	}
 }
+
+func TestFRSqrt(t *testing.T) {
+	modulus := RFieldModulus.ToBig()
+	squares := 0
+	for i := 0; i < 200; i++ {
+		f, _ := RandFR(rand.Reader)
+		fBig := f.ToRepr().ToBig()
+		expected := new(big.Int).ModSqrt(fBig, modulus)
+
+		root, ok := f.Sqrt()
+		if ok != (expected != nil) {
+			t.Fatalf("Sqrt of %s returned ok=%v, but big.ModSqrt disagrees", f, ok)
+		}
+		if f.IsSquare() != ok {
+			t.Fatal("IsSquare does not match Sqrt")
+		}
+		if !ok {
+			continue
+		}
+		squares++
+
+		rootBig := root.ToRepr().ToBig()
+		negExpected := new(big.Int).Sub(modulus, expected)
+		if rootBig.Cmp(expected) != 0 && rootBig.Cmp(negExpected) != 0 {
+			t.Fatal("Sqrt does not match big.ModSqrt")
+		}
+
+		root.SquareAssign()
+		if !root.Equals(f) {
+			t.Fatal("square of Sqrt should equal the original element")
+		}
+	}
+	if squares == 0 {
+		t.Fatal("expected some random elements to be squares")
+	}
+}
+
+func TestFRSqrtEdgeCases(t *testing.T) {
+	zero := FRReprToFR(NewFRRepr(0))
+	root, ok := zero.Sqrt()
+	if !ok || !root.IsZero() {
+		t.Fatal("square root of zero should be zero")
+	}
+
+	minusOne := bigZeroFR.Copy()
+	minusOne.SubAssign(bigOneFR)
+	root, ok = minusOne.Sqrt()
+	if !ok {
+		t.Fatal("-1 should be a square since r = 1 mod 4")
+	}
+	root.SquareAssign()
+	if !root.Equals(minusOne) {
+		t.Fatal("square of Sqrt(-1) should be -1")
+	}
+
+	seven := FRReprToFR(NewFRRepr(7))
+	if _, ok := seven.Sqrt(); ok {
+		t.Fatal("7 should not be a square")
+	}
+
+	// exercise the longest Tonelli-Shanks chains through the 2-adic subgroup
+	s := frRootOfUnity.Copy()
+	if _, ok := s.Sqrt(); ok {
+		t.Fatal("primitive root of unity should not be a square")
+	}
+	s.SquareAssign()
+	sq, ok := s.Sqrt()
+	if !ok {
+		t.Fatal("square of root of unity should be a square")
+	}
+	sq.SquareAssign()
+	if !sq.Equals(s) {
+		t.Fatal("square of Sqrt should equal the original element")
+	}
+}
@@ -1,7 +1,6 @@
 package bls
 
 import (
-	"errors"
 	"fmt"
 	"io"
 )
@@ -68,9 +67,9 @@ func (g G1Affine) Mul(b FQRepr) *G1Projective {
 	res := G1ProjectiveZero.Copy()
 	for i := uint(0); uint(i) < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o {
-			res = res.AddAffine(&g)
+			res.AddAffineAssign(&g)
 		}
 	}
 	return res
@@ -81,9 +80,9 @@ func (g G1Affine) MulFR(b *FRRepr) *G1Projective {
 	res := G1ProjectiveZero.Copy()
 	for i := uint(0); uint(i) < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o {
-			res = res.AddAffine(&g)
+			res.AddAffineAssign(&g)
 		}
 	}
 	return res
@@ -117,7 +116,7 @@ func GetG1PointFromX(x FQ, greatest bool) (*G1Affine, error) {
 	y, success := x3b.Sqrt()
 
 	if !success {
-		return nil, errors.New("point not on curve")
+		return nil, ErrNotOnCurve
 	}
 
 	negY := y.Copy()
@@ -189,7 +188,7 @@ func DecompressG1(b [48]byte) (*G1Affine, error) {
 	}
 
 	if !affine.IsInCorrectSubgroupAssumingOnCurve() {
-		return nil, errors.New("not in correct subgroup")
+		return nil, ErrNotInSubgroup
 	}
 	return affine, nil
 }
@@ -201,16 +200,19 @@ func DecompressG1Unchecked(b [48]byte) (*G1Affine, error) {
 	copy(copyBytes[:], b[:])
 
 	if copyBytes[0]&(1<<7) == 0 {
-		return nil, errors.New("unexpected compression mode")
+		return nil, ErrUnexpectedCompressionMode
 	}
 
 	if copyBytes[0]&(1<<6) != 0 {
 		// this is the point at infinity
+		if copyBytes[0]&(1<<5) != 0 {
+			return nil, ErrInfinitySortFlag
+		}
 		copyBytes[0] &= 0x3f
 
 		for _, b := range copyBytes {
 			if b != 0 {
-				return nil, errors.New("unexpected information in compressed infinity")
+				return nil, ErrInfinityNotZero
 			}
 		}
 
@@ -220,8 +222,10 @@ func DecompressG1Unchecked(b [48]byte) (*G1Affine, error) {
 
 	copyBytes[0] &= 0x1f
 
-	x := FQReprFromBytes(copyBytes)
-	xFQ := FQReprToFQ(x)
+	xFQ, err := getFQ(copyBytes[:])
+	if err != nil {
+		return nil, err
+	}
 
 	return GetG1PointFromX(xFQ, greatest)
 }
@@ -248,6 +252,72 @@ func CompressG1(affine *G1Affine) [48]byte {
 	return res
 }
 
+// SerializeG1Uncompressed serializes a G1 point as x || y with the
+// ZCash flag bits in the first byte.
+func SerializeG1Uncompressed(affine *G1Affine) [96]byte {
+	res := [96]byte{}
+	if affine.IsZero() {
+		res[0] |= 1 << 6
+		return res
+	}
+	putFQ(res[0:48], affine.x)
+	putFQ(res[48:96], affine.y)
+	return res
+}
+
+// DeserializeG1Uncompressed deserializes an uncompressed G1 point and
+// checks that it is on the curve and in the correct subgroup.
+func DeserializeG1Uncompressed(b [96]byte) (*G1Affine, error) {
+	affine, err := DeserializeG1UncompressedUnchecked(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !affine.IsOnCurve() {
+		return nil, ErrNotOnCurve
+	}
+
+	if !affine.IsInCorrectSubgroupAssumingOnCurve() {
+		return nil, ErrNotInSubgroup
+	}
+	return affine, nil
+}
+
+// DeserializeG1UncompressedUnchecked deserializes an uncompressed G1 point
+// without checking that it is on the curve or in the correct subgroup.
+func DeserializeG1UncompressedUnchecked(b [96]byte) (*G1Affine, error) {
+	if b[0]&(1<<7) != 0 {
+		return nil, ErrUnexpectedCompressionMode
+	}
+
+	if b[0]&(1<<6) != 0 {
+		if b[0]&(1<<5) != 0 {
+			return nil, ErrInfinitySortFlag
+		}
+		b[0] &= 0x3f
+		for _, v := range b {
+			if v != 0 {
+				return nil, ErrInfinityNotZero
+			}
+		}
+		return G1AffineZero.Copy(), nil
+	}
+
+	if b[0]&(1<<5) != 0 {
+		return nil, ErrUnexpectedSortFlag
+	}
+
+	x, err := getFQ(b[0:48])
+	if err != nil {
+		return nil, err
+	}
+	y, err := getFQ(b[48:96])
+	if err != nil {
+		return nil, err
+	}
+	return NewG1Affine(x, y), nil
+}
+
 // G1Projective is a projective point on the G1 curve.
 type G1Projective struct {
 	x FQ
@@ -339,10 +409,15 @@ func (g G1Projective) ToAffine() *G1Affine {
 	return NewG1Affine(x, y)
 }
 
-// Double performs EC doubling on the point.
-func (g G1Projective) Double() *G1Projective {
+// Set sets the point equal to another point.
+func (g *G1Projective) Set(other *G1Projective) {
+	*g = *other
+}
+
+// DoubleAssign performs EC doubling on the point in place.
+func (g *G1Projective) DoubleAssign() {
 	if g.IsZero() {
-		return g.Copy()
+		return
 	}
 
 	// A = x1^2
@@ -375,34 +450,40 @@ func (g G1Projective) Double() *G1Projective {
 	f.SquareAssign()
 
 	// z3 = 2*Y1*Z1
-	newZ := g.z.Copy()
-	newZ.MulAssign(g.y)
-	newZ.DoubleAssign()
+	g.z.MulAssign(g.y)
+	g.z.DoubleAssign()
 
 	// x3 = F-2*D
-	newX := f.Copy()
-	newX.SubAssign(d)
-	newX.SubAssign(d)
+	g.x = f
+	g.x.SubAssign(d)
+	g.x.SubAssign(d)
 
 	c.DoubleAssign()
 	c.DoubleAssign()
 	c.DoubleAssign()
 
-	newY := d.Copy()
-	newY.SubAssign(newX)
-	newY.MulAssign(e)
-	newY.SubAssign(c)
+	// y3 = E*(D-x3)-8*C
+	g.y = d
+	g.y.SubAssign(g.x)
+	g.y.MulAssign(e)
+	g.y.SubAssign(c)
+}
 
-	return NewG1Projective(newX, newY, newZ)
+// Double performs EC doubling on the point.
+func (g G1Projective) Double() *G1Projective {
+	out := g.Copy()
+	out.DoubleAssign()
+	return out
 }
 
-// Add performs an EC Add operation with another point.
-func (g G1Projective) Add(other *G1Projective) *G1Projective {
+// AddAssign performs an EC Add operation with another point in place.
+func (g *G1Projective) AddAssign(other *G1Projective) {
 	if g.IsZero() {
-		return other.Copy()
+		g.Set(other)
+		return
 	}
 	if other.IsZero() {
-		return g.Copy()
+		return
 	}
 
 	// Z1Z1 = Z1^2
@@ -433,7 +514,8 @@ func (g G1Projective) Add(other *G1Projective) *G1Projective {
 
 	if u1.Equals(u2) && s1.Equals(s2) {
 		// points are equal
-		return g.Double()
+		g.DoubleAssign()
+		return
 	}
 
 	// H = U2-U1
@@ -471,23 +553,43 @@ func (g G1Projective) Add(other *G1Projective) *G1Projective {
 	u1.SubAssign(s1)
 
 	// Z3 = ((Z1+Z2)^2 - Z1Z1 - Z2Z2)*H
-	newZ := g.z.Copy()
-	newZ.AddAssign(other.z)
-	newZ.SquareAssign()
-	newZ.SubAssign(z1z1)
-	newZ.SubAssign(z2z2)
-	newZ.MulAssign(h)
+	g.z.AddAssign(other.z)
+	g.z.SquareAssign()
+	g.z.SubAssign(z1z1)
+	g.z.SubAssign(z2z2)
+	g.z.MulAssign(h)
 
-	return NewG1Projective(newX, u1, newZ)
+	g.x = newX
+	g.y = u1
 }
 
-// AddAffine performs an EC Add operation with an affine point.
-func (g G1Projective) AddAffine(other *G1Affine) *G1Projective {
-	if g.IsZero() {
-		return other.ToProjective()
+// Add performs an EC Add operation with another point.
+func (g G1Projective) Add(other *G1Projective) *G1Projective {
+	out := g.Copy()
+	out.AddAssign(other)
+	return out
+}
+
+// SetAdd sets the point to a + b. Either argument may be the receiver.
+func (g *G1Projective) SetAdd(a, b *G1Projective) {
+	if g == b {
+		g.AddAssign(a)
+		return
 	}
+	g.Set(a)
+	g.AddAssign(b)
+}
+
+// AddAffineAssign performs an EC Add operation with an affine point in place.
+func (g *G1Projective) AddAffineAssign(other *G1Affine) {
 	if other.IsZero() {
-		return g.Copy()
+		return
+	}
+	if g.IsZero() {
+		g.x = other.x
+		g.y = other.y
+		g.z = FQOne
+		return
 	}
 
 	// Z1Z1 = Z1^2
@@ -505,7 +607,8 @@ func (g G1Projective) AddAffine(other *G1Affine) *G1Projective {
 
 	if g.x.Equals(u2) && g.y.Equals(s2) {
 		// points are equal
-		return g.Double()
+		g.DoubleAssign()
+		return
 	}
 
 	// H = U2-X1
@@ -549,13 +652,20 @@ func (g G1Projective) AddAffine(other *G1Affine) *G1Projective {
 	newY.SubAssign(i0)
 
 	// Z3 = (Z1+H)^2 - Z1Z1 - HH
-	newZ := g.z.Copy()
-	newZ.AddAssign(u2)
-	newZ.SquareAssign()
-	newZ.SubAssign(z1z1)
-	newZ.SubAssign(hh)
+	g.z.AddAssign(u2)
+	g.z.SquareAssign()
+	g.z.SubAssign(z1z1)
+	g.z.SubAssign(hh)
 
-	return NewG1Projective(newX, newY, newZ)
+	g.x = newX
+	g.y = newY
+}
+
+// AddAffine performs an EC Add operation with an affine point.
+func (g G1Projective) AddAffine(other *G1Affine) *G1Projective {
+	out := g.Copy()
+	out.AddAffineAssign(other)
+	return out
 }
 
 // Mul performs a EC multiply operation on the point.
@@ -563,25 +673,32 @@ func (g G1Projective) Mul(b FQRepr) *G1Projective {
 	res := G1ProjectiveZero.Copy()
 	for i := uint(0); i < uint(b.BitLen()); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o {
-			res = res.Add(&g)
+			res.AddAssign(&g)
 		}
 	}
 	return res
 }
 
-// MulFR performs a EC multiply operation on the point.
-func (g G1Projective) MulFR(b *FRRepr) *G1Projective {
-	res := G1ProjectiveZero.Copy()
+// MulFRAssign performs a EC multiply operation on the point in place.
+func (g *G1Projective) MulFRAssign(b *FRRepr) {
+	base := *g
+	g.Set(G1ProjectiveZero)
 	for i := uint(0); i < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		g.DoubleAssign()
 		if o {
-			res = res.Add(&g)
+			g.AddAssign(&base)
 		}
 	}
-	return res
+}
+
+// MulFR performs a EC multiply operation on the point.
+func (g G1Projective) MulFR(b *FRRepr) *G1Projective {
+	out := g.Copy()
+	out.MulFRAssign(b)
+	return out
 }
 
 // RandG1 generates a random G1 element.
@@ -1,6 +1,8 @@
 package bls_test
 
 import (
+	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/phoreproject/bls"
@@ -103,6 +105,244 @@ func TestG1AdditionCorrectness(t *testing.T) {
 	}
 }
 
+func TestG1InPlaceMatchesAllocating(t *testing.T) {
+	r := NewXORShift(1)
+	for i := 0; i < 20; i++ {
+		a, _ := bls.RandG1(r)
+		b, _ := bls.RandG1(r)
+
+		sum := a.Copy()
+		sum.AddAssign(b)
+		if !sum.Equal(a.Add(b)) {
+			t.Fatal("AddAssign does not match Add")
+		}
+
+		sum.SetAdd(a, b)
+		if !sum.Equal(a.Add(b)) {
+			t.Fatal("SetAdd does not match Add")
+		}
+
+		sum.Set(a)
+		sum.SetAdd(b, sum)
+		if !sum.Equal(a.Add(b)) {
+			t.Fatal("SetAdd with the receiver as an argument does not match Add")
+		}
+
+		double := a.Copy()
+		double.DoubleAssign()
+		if !double.Equal(a.Double()) {
+			t.Fatal("DoubleAssign does not match Double")
+		}
+
+		self := a.Copy()
+		self.AddAssign(self)
+		if !self.Equal(double) {
+			t.Fatal("adding a point to itself should double it")
+		}
+
+		mixed := a.Copy()
+		mixed.AddAffineAssign(b.ToAffine())
+		if !mixed.Equal(a.Add(b)) {
+			t.Fatal("AddAffineAssign does not match Add")
+		}
+
+		f, _ := bls.RandFR(r)
+		product := a.Copy()
+		product.MulFRAssign(f.ToRepr())
+		if !product.Equal(a.ToAffine().MulFR(f.ToRepr())) {
+			t.Fatal("MulFRAssign does not match MulFR")
+		}
+	}
+}
+
+func TestG1AddAssignZero(t *testing.T) {
+	p := bls.G1ProjectiveZero.Copy()
+	p.AddAssign(bls.G1ProjectiveOne)
+	if !p.Equal(bls.G1ProjectiveOne) {
+		t.Fatal("0 + g should equal g")
+	}
+
+	p.AddAssign(bls.G1ProjectiveZero)
+	if !p.Equal(bls.G1ProjectiveOne) {
+		t.Fatal("g + 0 should equal g")
+	}
+
+	neg := bls.G1ProjectiveOne.Copy()
+	neg.NegAssign()
+	p.AddAssign(neg)
+	if !p.IsZero() {
+		t.Fatal("g + -g should equal zero")
+	}
+
+	p.AddAffineAssign(bls.G1AffineOne)
+	if !p.Equal(bls.G1ProjectiveOne) {
+		t.Fatal("0 + g should equal g")
+	}
+}
+
+func TestG1InPlaceDoesNotAllocate(t *testing.T) {
+	a := bls.G1ProjectiveOne.Copy()
+	b := bls.G1ProjectiveOne.Double()
+	c := bls.G1AffineOne.Copy()
+	allocs := testing.AllocsPerRun(100, func() {
+		a.AddAssign(b)
+		a.DoubleAssign()
+		a.AddAffineAssign(c)
+		a.SetAdd(a, b)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected in-place operations not to allocate, got %f allocations", allocs)
+	}
+}
+
+func TestG1UncompressedGenerator(t *testing.T) {
+	expected := "17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb" +
+		"08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1"
+	out := bls.SerializeG1Uncompressed(bls.G1AffineOne)
+	if hex.EncodeToString(out[:]) != expected {
+		t.Fatalf("unexpected uncompressed generator %x", out)
+	}
+
+	g, err := bls.DeserializeG1Uncompressed(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equals(bls.G1AffineOne) {
+		t.Fatal("generator did not round trip")
+	}
+}
+
+func TestG1UncompressedRoundTrip(t *testing.T) {
+	r := NewXORShift(1)
+	for i := 0; i < 10; i++ {
+		p, _ := bls.RandG1(r)
+		a := p.ToAffine()
+		b := bls.SerializeG1Uncompressed(a)
+		if b[0]&0xe0 != 0 {
+			t.Fatal("flag bits should be clear for an uncompressed point")
+		}
+		a2, err := bls.DeserializeG1Uncompressed(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a2.Equals(a) {
+			t.Fatal("point did not round trip")
+		}
+	}
+
+	inf := bls.SerializeG1Uncompressed(bls.G1AffineZero)
+	if inf[0] != 0x40 {
+		t.Fatal("infinity should have only the infinity flag set")
+	}
+	a, err := bls.DeserializeG1Uncompressed(inf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsZero() {
+		t.Fatal("infinity did not round trip")
+	}
+}
+
+func TestG1UncompressedInvalid(t *testing.T) {
+	valid := bls.SerializeG1Uncompressed(bls.G1AffineOne)
+
+	compressed := valid
+	compressed[0] |= 0x80
+	if _, err := bls.DeserializeG1Uncompressed(compressed); err == nil {
+		t.Fatal("expected error with compression flag set")
+	}
+
+	sorted := valid
+	sorted[0] |= 0x20
+	if _, err := bls.DeserializeG1Uncompressed(sorted); err == nil {
+		t.Fatal("expected error with sort flag set")
+	}
+
+	inf := bls.SerializeG1Uncompressed(bls.G1AffineZero)
+	inf[95] = 1
+	if _, err := bls.DeserializeG1Uncompressed(inf); err == nil {
+		t.Fatal("expected error with data in infinity")
+	}
+
+	offCurve := valid
+	offCurve[95] ^= 1
+	if _, err := bls.DeserializeG1Uncompressed(offCurve); err == nil {
+		t.Fatal("expected error for point not on curve")
+	}
+	if _, err := bls.DeserializeG1UncompressedUnchecked(offCurve); err != nil {
+		t.Fatal("unchecked deserialization should not check the curve equation")
+	}
+
+	// (0, 2) is on the curve but not in the prime order subgroup
+	var notInSubgroup [96]byte
+	notInSubgroup[95] = 2
+	if _, err := bls.DeserializeG1Uncompressed(notInSubgroup); err == nil {
+		t.Fatal("expected error for point not in subgroup")
+	}
+
+	tooLarge := valid
+	modulus := bls.QFieldModulus.Bytes()
+	copy(tooLarge[:48], modulus[:])
+	if _, err := bls.DeserializeG1UncompressedUnchecked(tooLarge); err == nil {
+		t.Fatal("expected error for coordinate not less than the modulus")
+	}
+}
+
+func TestDecompressG1NonCanonical(t *testing.T) {
+	valid := bls.CompressG1(bls.G1AffineOne)
+
+	notCompressed := valid
+	notCompressed[0] &= 0x7f
+	if _, err := bls.DecompressG1(notCompressed); !errors.Is(err, bls.ErrUnexpectedCompressionMode) {
+		t.Fatalf("expected ErrUnexpectedCompressionMode, got %v", err)
+	}
+
+	var infinitySorted [48]byte
+	infinitySorted[0] = 0xe0
+	if _, err := bls.DecompressG1(infinitySorted); !errors.Is(err, bls.ErrInfinitySortFlag) {
+		t.Fatalf("expected ErrInfinitySortFlag, got %v", err)
+	}
+
+	infinityData := bls.CompressG1(bls.G1AffineZero)
+	infinityData[47] = 1
+	if _, err := bls.DecompressG1(infinityData); !errors.Is(err, bls.ErrInfinityNotZero) {
+		t.Fatalf("expected ErrInfinityNotZero, got %v", err)
+	}
+
+	// x = p would otherwise be reduced to the valid x-coordinate 0
+	tooLarge := bls.QFieldModulus.Bytes()
+	tooLarge[0] |= 0x80
+	if _, err := bls.DecompressG1Unchecked(tooLarge); !errors.Is(err, bls.ErrNonCanonicalCoordinate) {
+		t.Fatalf("expected ErrNonCanonicalCoordinate, got %v", err)
+	}
+
+	if _, err := bls.DecompressG1(valid); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecompressG1CurveErrors(t *testing.T) {
+	// x = 0 gives the point (0, 2) of order 3
+	var orderThree [48]byte
+	orderThree[0] = 0x80
+	if _, err := bls.DecompressG1(orderThree); !errors.Is(err, bls.ErrNotInSubgroup) {
+		t.Fatalf("expected ErrNotInSubgroup, got %v", err)
+	}
+
+	var b [48]byte
+	for x := byte(1); ; x++ {
+		b[0] = 0x80
+		b[47] = x
+		_, err := bls.DecompressG1Unchecked(b)
+		if err != nil {
+			if !errors.Is(err, bls.ErrNotOnCurve) {
+				t.Fatalf("expected ErrNotOnCurve, got %v", err)
+			}
+			break
+		}
+	}
+}
+
 type XORShift struct {
 	state uint64
 }
@@ -1,9 +1,14 @@
 package g1pubs_test
 
 import (
+	"bytes"
+	"crypto"
+	"encoding/asn1"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/phoreproject/bls"
@@ -459,3 +464,895 @@ func TestConvertSignatureToFromPoint(t *testing.T) {
 		t.Fatal("expected sig -> point -> sig to return the same public key.")
 	}
 }
+
+func TestAggregationDoesNotAllocatePerItem(t *testing.T) {
+	sigs := make([]*g1pubs.Signature, 10000)
+	pubs := make([]*g1pubs.PublicKey, 10000)
+	sigPoint := bls.G2ProjectiveOne.Copy()
+	pubPoint := bls.G1ProjectiveOne.Copy()
+	for i := range sigs {
+		sigs[i] = g1pubs.NewSignatureFromG2(sigPoint.ToAffine())
+		pubs[i] = g1pubs.NewPublicKeyFromG1(pubPoint.ToAffine())
+		sigPoint.AddAssign(bls.G2ProjectiveOne)
+		pubPoint.AddAssign(bls.G1ProjectiveOne)
+	}
+
+	allocs := testing.AllocsPerRun(1, func() {
+		g1pubs.AggregateSignatures(sigs)
+	})
+	if allocs > 2 {
+		t.Fatalf("expected a constant number of allocations aggregating signatures, got %f", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(1, func() {
+		g1pubs.AggregatePublicKeys(pubs)
+	})
+	if allocs > 2 {
+		t.Fatalf("expected a constant number of allocations aggregating public keys, got %f", allocs)
+	}
+}
+
+func TestUncompressedSerializeDeserialize(t *testing.T) {
+	r := NewXORShift(1)
+	priv, _ := g1pubs.RandKey(r)
+	pub := g1pubs.PrivToPub(priv)
+	msg := []byte("uncompressed message")
+	sig := g1pubs.Sign(msg, priv)
+
+	pubDeser, err := g1pubs.DeserializePublicKeyUncompressed(pub.SerializeUncompressed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigDeser, err := g1pubs.DeserializeSignatureUncompressed(sig.SerializeUncompressed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1pubs.Verify(msg, pubDeser, sigDeser) {
+		t.Fatal("message did not verify after uncompressed serialization/deserialization")
+	}
+
+	var invalidPub [96]byte
+	invalidPub[0] = 0x80
+	if _, err := g1pubs.DeserializePublicKeyUncompressed(invalidPub); err == nil {
+		t.Fatal("expected deserialization of invalid uncompressed pubkey to fail")
+	}
+	var invalidSig [192]byte
+	invalidSig[192-1] = 1
+	if _, err := g1pubs.DeserializeSignatureUncompressed(invalidSig); err == nil {
+		t.Fatal("expected deserialization of invalid uncompressed signature to fail")
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	r := NewXORShift(5)
+	priv, _ := g1pubs.RandKey(r)
+	pub := g1pubs.PrivToPub(priv)
+	msg := []byte("message to verify")
+	sig := g1pubs.Sign(msg, priv)
+
+	if err := g1pubs.VerifyE(msg, pub, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g1pubs.VerifyE([]byte("other message"), pub, sig); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	if err := g1pubs.VerifyE(msg, g1pubs.NewAggregatePubkey(), sig); !errors.Is(err, g1pubs.ErrIdentityKey) {
+		t.Fatalf("expected ErrIdentityKey, got %v", err)
+	}
+}
+
+func TestVerifyAggregateErrors(t *testing.T) {
+	r := NewXORShift(6)
+	priv1, _ := g1pubs.RandKey(r)
+	priv2, _ := g1pubs.RandKey(r)
+	pub1 := g1pubs.PrivToPub(priv1)
+	pub2 := g1pubs.PrivToPub(priv2)
+	msg1 := []byte("first message")
+	msg2 := []byte("second message")
+	sig := g1pubs.AggregateSignatures([]*g1pubs.Signature{g1pubs.Sign(msg1, priv1), g1pubs.Sign(msg2, priv2)})
+
+	pubs := []*g1pubs.PublicKey{pub1, pub2}
+	if err := sig.VerifyAggregateE(pubs, [][]byte{msg1, msg2}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sig.VerifyAggregateE(pubs, [][]byte{msg1}); !errors.Is(err, g1pubs.ErrMessageCountMismatch) {
+		t.Fatalf("expected ErrMessageCountMismatch, got %v", err)
+	}
+
+	if err := sig.VerifyAggregateE(pubs, [][]byte{msg1, msg1}); !errors.Is(err, g1pubs.ErrDuplicateMessage) {
+		t.Fatalf("expected ErrDuplicateMessage, got %v", err)
+	}
+
+	if err := sig.VerifyAggregateE(pubs, [][]byte{msg2, msg1}); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	withIdentity := []*g1pubs.PublicKey{pub1, g1pubs.NewAggregatePubkey()}
+	if err := sig.VerifyAggregateE(withIdentity, [][]byte{msg1, msg2}); !errors.Is(err, g1pubs.ErrIdentityKey) {
+		t.Fatalf("expected ErrIdentityKey, got %v", err)
+	}
+
+	common := g1pubs.AggregateSignatures([]*g1pubs.Signature{g1pubs.Sign(msg1, priv1), g1pubs.Sign(msg1, priv2)})
+	if err := common.VerifyAggregateCommonE(pubs, msg1); err != nil {
+		t.Fatal(err)
+	}
+	if err := common.VerifyAggregateCommonE(pubs, msg2); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if err := common.VerifyAggregateCommonE(nil, msg1); !errors.Is(err, g1pubs.ErrIdentityKey) {
+		t.Fatalf("expected ErrIdentityKey for empty aggregate, got %v", err)
+	}
+
+	domain := [8]byte{1}
+	m1 := [32]byte{1}
+	m2 := [32]byte{2}
+	domainSig := g1pubs.AggregateSignatures([]*g1pubs.Signature{g1pubs.SignWithDomain(m1, priv1, domain), g1pubs.SignWithDomain(m2, priv2, domain)})
+	if err := domainSig.VerifyAggregateWithDomainE(pubs, [][32]byte{m1, m2}, domain); err != nil {
+		t.Fatal(err)
+	}
+	if err := domainSig.VerifyAggregateWithDomainE(pubs, [][32]byte{m1}, domain); !errors.Is(err, g1pubs.ErrMessageCountMismatch) {
+		t.Fatalf("expected ErrMessageCountMismatch, got %v", err)
+	}
+	if err := domainSig.VerifyAggregateWithDomainE(pubs, [][32]byte{m1, m1}, domain); !errors.Is(err, g1pubs.ErrDuplicateMessage) {
+		t.Fatalf("expected ErrDuplicateMessage, got %v", err)
+	}
+}
+
+func TestDeserializeErrors(t *testing.T) {
+	var notCompressed [48]byte
+	if _, err := g1pubs.DeserializePublicKey(notCompressed); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	r := NewXORShift(7)
+	priv, _ := g1pubs.RandKey(r)
+	pub := g1pubs.PrivToPub(priv)
+	msg := []byte("marshalled message")
+	sig := g1pubs.Sign(msg, priv)
+
+	type keyFile struct {
+		Secret    *g1pubs.SecretKey `json:"secret"`
+		Public    *g1pubs.PublicKey `json:"public"`
+		Signature *g1pubs.Signature `json:"signature"`
+	}
+
+	j, err := json.Marshal(keyFile{priv, pub, sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded keyFile
+	if err := json.Unmarshal(j, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Secret.Serialize() != priv.Serialize() {
+		t.Fatal("secret key did not round trip through JSON")
+	}
+	if !decoded.Public.Equals(*pub) {
+		t.Fatal("public key did not round trip through JSON")
+	}
+	if !g1pubs.Verify(msg, decoded.Public, decoded.Signature) {
+		t.Fatal("signature did not verify after JSON round trip")
+	}
+
+	pubBytes, _ := pub.MarshalBinary()
+	var pubFromBinary g1pubs.PublicKey
+	if err := pubFromBinary.UnmarshalBinary(pubBytes); err != nil {
+		t.Fatal(err)
+	}
+	sigBytes, _ := sig.MarshalBinary()
+	var sigFromBinary g1pubs.Signature
+	if err := sigFromBinary.UnmarshalBinary(sigBytes); err != nil {
+		t.Fatal(err)
+	}
+	if !g1pubs.Verify(msg, &pubFromBinary, &sigFromBinary) {
+		t.Fatal("signature did not verify after binary round trip")
+	}
+}
+
+func TestUnmarshalSecretKeyInvalid(t *testing.T) {
+	var k g1pubs.SecretKey
+	order := bls.RFieldModulus.Bytes()
+	if err := k.UnmarshalBinary(order[:]); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for key equal to the group order, got %v", err)
+	}
+	if err := k.UnmarshalText([]byte("0x01")); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for short key, got %v", err)
+	}
+}
+
+func TestDeserializeSecretKeyStrict(t *testing.T) {
+	r := NewXORShift(8)
+	priv, _ := g1pubs.RandKey(r)
+	k, err := g1pubs.DeserializeSecretKeyStrict(priv.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Serialize() != priv.Serialize() {
+		t.Fatal("secret key did not round trip")
+	}
+
+	if _, err := g1pubs.DeserializeSecretKeyStrict([32]byte{}); !errors.Is(err, g1pubs.ErrZeroSecretKey) {
+		t.Fatalf("expected ErrZeroSecretKey, got %v", err)
+	}
+
+	if _, err := g1pubs.DeserializeSecretKeyStrict(bls.RFieldModulus.Bytes()); !errors.Is(err, g1pubs.ErrSecretKeyOutOfRange) {
+		t.Fatalf("expected ErrSecretKeyOutOfRange, got %v", err)
+	}
+
+	var allOnes [32]byte
+	for i := range allOnes {
+		allOnes[i] = 0xff
+	}
+	if _, err := g1pubs.DeserializeSecretKeyStrict(allOnes); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding, got %v", err)
+	}
+	if k := g1pubs.DeserializeSecretKey(allOnes); k != nil {
+		t.Fatal("expected nil for a key that is not less than the group order")
+	}
+	if k := g1pubs.DeserializeSecretKey([32]byte{}); k != nil {
+		t.Fatal("expected nil for a zero key")
+	}
+	if k := g1pubs.KeyFromFQRepr(bls.RFieldModulus); k != nil {
+		t.Fatal("expected nil for the group order")
+	}
+}
+
+func TestRandKeyRejectsOutOfRange(t *testing.T) {
+	// the first candidate is r, the second is zero and the third is one
+	order := bls.RFieldModulus.Bytes()
+	var zero, one [32]byte
+	one[31] = 1
+	reader := bytes.NewReader(append(append(order[:], zero[:]...), one[:]...))
+
+	k, err := g1pubs.RandKey(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Serialize() != one {
+		t.Fatal("expected rejection sampling to skip r and zero")
+	}
+
+	if _, err := g1pubs.RandKey(bytes.NewReader(order[:])); err == nil {
+		t.Fatal("expected error when the reader runs out")
+	}
+}
+
+func TestSecretKeyRedacted(t *testing.T) {
+	r := NewXORShift(9)
+	priv, _ := g1pubs.RandKey(r)
+	b := priv.Serialize()
+	secretHex := hex.EncodeToString(b[:])
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%x", "%X", "%q", "%d"} {
+		out := fmt.Sprintf(format, priv)
+		if strings.Contains(strings.ToLower(out), secretHex) {
+			t.Fatalf("format %s leaked key material: %s", format, out)
+		}
+		out = fmt.Sprintf(format, *priv)
+		if strings.Contains(strings.ToLower(out), secretHex) {
+			t.Fatalf("format %s leaked key material: %s", format, out)
+		}
+	}
+
+	if priv.Reveal() != "0x"+secretHex {
+		t.Fatal("Reveal should return the key as hex")
+	}
+}
+
+func TestSecretKeyZeroize(t *testing.T) {
+	r := NewXORShift(10)
+	priv, _ := g1pubs.RandKey(r)
+	priv.Zeroize()
+	if priv.Serialize() != [32]byte{} {
+		t.Fatal("zeroized key should serialize to zeros")
+	}
+	if !priv.GetScalar().IsZero() {
+		t.Fatal("zeroized key should have a zero scalar")
+	}
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(40))
+	signer := g1pubs.NewSigner(priv)
+	var cs crypto.Signer = signer
+
+	pub, ok := cs.Public().(*g1pubs.PublicKey)
+	if !ok {
+		t.Fatal("signer public key should be a *PublicKey")
+	}
+	if !pub.Equal(g1pubs.PrivToPub(priv)) {
+		t.Fatal("signer public key should match the secret key")
+	}
+
+	msg := []byte("crypto.Signer message")
+	sig, err := cs.Sign(nil, msg, crypto.Hash(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := g1pubs.Sign(msg, priv).Serialize()
+	if !bytes.Equal(sig, expected[:]) {
+		t.Fatal("signer without a tag should match Sign")
+	}
+
+	var v g1pubs.Verifier = pub
+	if err := v.Verify(msg, sig, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Verify([]byte("other message"), sig, nil); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+	if err := v.Verify(msg, sig[1:], nil); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding, got %v", err)
+	}
+}
+
+func TestCiphersuitePOPVector(t *testing.T) {
+	// sign_case_84d45c9c7cca6b92 of the Ethereum consensus spec tests.
+	b, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	var keyBytes [32]byte
+	copy(keyBytes[:], b)
+	priv := g1pubs.DeserializeSecretKey(keyBytes)
+	msg := make([]byte, 32)
+
+	sig, err := g1pubs.SignWithDST(msg, priv, []byte(g1pubs.CiphersuitePOP))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sig.Serialize()
+	expected := "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55"
+	if hex.EncodeToString(s[:]) != expected {
+		t.Fatalf("unexpected signature %x", s)
+	}
+	if err := g1pubs.VerifyWithDST(msg, g1pubs.PrivToPub(priv), sig, []byte(g1pubs.CiphersuitePOP)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSignerDST(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(41))
+	signer := g1pubs.NewSigner(priv)
+	pub := signer.Public().(*g1pubs.PublicKey)
+	msg := []byte("crypto.Signer message")
+
+	optsA := &g1pubs.SignerOpts{DST: []byte("DST-A")}
+	optsB := &g1pubs.SignerOpts{DST: []byte("DST-B")}
+	sig, err := signer.Sign(nil, msg, optsA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.Verify(msg, sig, optsA); err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.Verify(msg, sig, optsB); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatal("signature should not verify with a different tag")
+	}
+	if err := pub.Verify(msg, sig, nil); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatal("signature should not verify with the default ciphersuite")
+	}
+
+	// A tagged signature must not verify as a signature on the tag
+	// followed by the message.
+	dst := []byte("D")
+	tagged, err := g1pubs.SignWithDST(msg, priv, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g1pubs.VerifyE(append(dst, msg...), pub, tagged); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatal("signature with a tag should not verify as an untagged signature")
+	}
+
+	if _, err := signer.Sign(nil, msg, crypto.SHA256); !errors.Is(err, g1pubs.ErrUnsupportedSignerOpts) {
+		t.Fatalf("expected ErrUnsupportedSignerOpts, got %v", err)
+	}
+	long := &g1pubs.SignerOpts{DST: make([]byte, bls.MaxDSTLength+1)}
+	if _, err := signer.Sign(nil, msg, long); !errors.Is(err, g1pubs.ErrDSTTooLong) {
+		t.Fatalf("expected ErrDSTTooLong, got %v", err)
+	}
+}
+
+func TestPublicKeyEqual(t *testing.T) {
+	priv1, _ := g1pubs.RandKey(NewXORShift(42))
+	priv2, _ := g1pubs.RandKey(NewXORShift(43))
+	pub1 := g1pubs.PrivToPub(priv1)
+	if !pub1.Equal(g1pubs.PrivToPub(priv1)) {
+		t.Fatal("public keys from the same secret key should be equal")
+	}
+	if pub1.Equal(g1pubs.PrivToPub(priv2)) {
+		t.Fatal("public keys from different secret keys should not be equal")
+	}
+	if pub1.Equal(*pub1) || pub1.Equal(nil) {
+		t.Fatal("only *PublicKey values should compare equal")
+	}
+}
+
+// Arbitrary object identifiers for the tests.
+var (
+	testOID, _  = bls.NewOID(asn1.ObjectIdentifier{1, 2, 3, 4, 1})
+	otherOID, _ = bls.NewOID(asn1.ObjectIdentifier{1, 2, 3, 4, 2})
+)
+
+func TestPKIXPublicKeyRoundTrip(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(50))
+	pub := g1pubs.PrivToPub(priv)
+
+	der, err := g1pubs.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, err := g1pubs.ParsePKIXPublicKey(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(pub2) {
+		t.Fatal("public key did not round trip through DER")
+	}
+
+	pemBytes, err := g1pubs.EncodePublicKeyPEM(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pemBytes, []byte("-----BEGIN PUBLIC KEY-----")) {
+		t.Fatal("expected a PUBLIC KEY PEM block")
+	}
+	pub3, err := g1pubs.DecodePublicKeyPEM(pemBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(pub3) {
+		t.Fatal("public key did not round trip through PEM")
+	}
+
+	defaultOID, _ := bls.ParseOID("2.25.209824747550876313493300493992439978690.1")
+	if _, err := bls.ParseSPKI(der, defaultOID); err != nil {
+		t.Fatalf("unexpected default object identifier: %v", err)
+	}
+	withOID, err := g1pubs.MarshalPKIXPublicKeyWithOID(pub, testOID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub4, err := g1pubs.ParsePKIXPublicKeyWithOID(withOID, testOID); err != nil || !pub.Equal(pub4) {
+		t.Fatalf("public key did not round trip with an object identifier: %v", err)
+	}
+	if _, err := g1pubs.ParsePKIXPublicKey(withOID); !errors.Is(err, g1pubs.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+
+	b := pub.Serialize()
+	other, _ := bls.MarshalSPKI(otherOID, b[:])
+	if _, err := g1pubs.ParsePKIXPublicKeyWithOID(other, testOID); !errors.Is(err, g1pubs.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+	b[len(b)-1] ^= 1
+	bad, _ := bls.MarshalSPKI(testOID, b[:])
+	if _, err := g1pubs.ParsePKIXPublicKeyWithOID(bad, testOID); err == nil {
+		t.Fatal("expected error parsing an invalid point")
+	}
+}
+
+func TestPKCS8SecretKeyRoundTrip(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(51))
+
+	der, err := g1pubs.MarshalPKCS8SecretKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv2, err := g1pubs.ParsePKCS8SecretKey(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv.Serialize() != priv2.Serialize() {
+		t.Fatal("secret key did not round trip through DER")
+	}
+
+	pemBytes, err := g1pubs.EncodeSecretKeyPEM(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv3, err := g1pubs.DecodeSecretKeyPEM(pemBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv.Serialize() != priv3.Serialize() {
+		t.Fatal("secret key did not round trip through PEM")
+	}
+	pemWithOID, err := g1pubs.EncodeSecretKeyPEMWithOID(priv, testOID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if priv4, err := g1pubs.DecodeSecretKeyPEMWithOID(pemWithOID, testOID); err != nil || priv.Serialize() != priv4.Serialize() {
+		t.Fatalf("secret key did not round trip with an object identifier: %v", err)
+	}
+	if _, err := g1pubs.DecodeSecretKeyPEM(pemWithOID); !errors.Is(err, g1pubs.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+
+	if _, err := g1pubs.DecodePublicKeyPEMWithOID(pemBytes, testOID); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for the wrong PEM type, got %v", err)
+	}
+	if _, err := g1pubs.DecodeSecretKeyPEMWithOID([]byte("not PEM"), testOID); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding without a PEM block, got %v", err)
+	}
+
+	zero, _ := bls.MarshalPKCS8(testOID, make([]byte, 32))
+	if _, err := g1pubs.ParsePKCS8SecretKeyWithOID(zero, testOID); !errors.Is(err, g1pubs.ErrZeroSecretKey) {
+		t.Fatalf("expected ErrZeroSecretKey, got %v", err)
+	}
+	short, _ := bls.MarshalPKCS8(testOID, make([]byte, 31))
+	if _, err := g1pubs.ParsePKCS8SecretKeyWithOID(short, testOID); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for a short key, got %v", err)
+	}
+}
+
+func TestJWKRoundTrip(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(60))
+	pub := g1pubs.PrivToPub(priv)
+
+	for _, kty := range []string{bls.JWKKeyTypeOKP, bls.JWKKeyTypeEC} {
+		jwk, err := g1pubs.SecretKeyToJWK(priv, kty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if jwk.Crv != bls.JWKCurveG1 || jwk.D == "" {
+			t.Fatal("unexpected curve or missing d in secret key JWK")
+		}
+		data, err := json.Marshal(jwk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded bls.JWK
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		priv2, err := g1pubs.SecretKeyFromJWK(&decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if priv.Serialize() != priv2.Serialize() {
+			t.Fatalf("secret key did not round trip through %s JWK", kty)
+		}
+		pub2, err := g1pubs.PublicKeyFromJWK(&decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(pub2) {
+			t.Fatalf("public key did not round trip through %s JWK", kty)
+		}
+
+		pubJWK, err := g1pubs.PublicKeyToJWK(pub, kty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pubJWK.D != "" {
+			t.Fatal("public key JWK should not have a d member")
+		}
+		if (kty == bls.JWKKeyTypeEC) != (pubJWK.Y != "") {
+			t.Fatal("only EC JWKs should have a y member")
+		}
+	}
+}
+
+func TestJWKInvalid(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(61))
+	other, _ := g1pubs.RandKey(NewXORShift(62))
+
+	jwk, _ := g1pubs.SecretKeyToJWK(priv, bls.JWKKeyTypeOKP)
+	otherJWK, _ := g1pubs.SecretKeyToJWK(other, bls.JWKKeyTypeOKP)
+	mismatched := *jwk
+	mismatched.D = otherJWK.D
+	if _, err := g1pubs.SecretKeyFromJWK(&mismatched); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for mismatched d, got %v", err)
+	}
+
+	wrongCurve := *jwk
+	wrongCurve.Crv = bls.JWKCurveG2
+	if _, err := g1pubs.PublicKeyFromJWK(&wrongCurve); !errors.Is(err, g1pubs.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+
+	wrongType := *jwk
+	wrongType.Kty = "RSA"
+	if _, err := g1pubs.PublicKeyFromJWK(&wrongType); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for an unsupported key type, got %v", err)
+	}
+	if _, err := g1pubs.PublicKeyToJWK(g1pubs.PrivToPub(priv), "RSA"); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for an unsupported key type, got %v", err)
+	}
+
+	noD := *jwk
+	noD.D = ""
+	if _, err := g1pubs.SecretKeyFromJWK(&noD); !errors.Is(err, g1pubs.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding without d, got %v", err)
+	}
+}
+
+func TestJWS(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(63))
+	pub := g1pubs.PrivToPub(priv)
+	payload := []byte(`{"sub":"1234567890","admin":true}`)
+
+	token, err := g1pubs.SignJWS(payload, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := g1pubs.VerifyJWS(token, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, payload) {
+		t.Fatal("payload did not round trip through JWS")
+	}
+
+	other, _ := g1pubs.RandKey(NewXORShift(64))
+	if _, err := g1pubs.VerifyJWS(token, g1pubs.PrivToPub(other)); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for the wrong key, got %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + "." + bls.EncodeBase64URL([]byte(`{"sub":"1234567890","admin":false}`)) + "." + parts[2]
+	if _, err := g1pubs.VerifyJWS(tampered, pub); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for a tampered payload, got %v", err)
+	}
+
+	none := bls.EncodeBase64URL([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	if _, err := g1pubs.VerifyJWS(none, pub); !errors.Is(err, g1pubs.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm for alg none, got %v", err)
+	}
+
+	// a plain signature over the signing input must not verify as a JWS
+	plain := g1pubs.Sign([]byte(parts[0]+"."+parts[1]), priv).Serialize()
+	forged := parts[0] + "." + parts[1] + "." + bls.EncodeBase64URL(plain[:])
+	if _, err := g1pubs.VerifyJWS(forged, pub); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for a signature without the JWS tag, got %v", err)
+	}
+}
+
+func TestProofOfPossession(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(70))
+	pub := g1pubs.PrivToPub(priv)
+	proof := g1pubs.ProvePossession(priv)
+	if err := g1pubs.VerifyPossession(pub, proof); err != nil {
+		t.Fatal(err)
+	}
+
+	other, _ := g1pubs.RandKey(NewXORShift(71))
+	if err := g1pubs.VerifyPossession(g1pubs.PrivToPub(other), proof); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for another key, got %v", err)
+	}
+
+	b := pub.Serialize()
+	if err := g1pubs.VerifyPossession(pub, g1pubs.Sign(b[:], priv)); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatal("a plain signature of the public key should not be a proof of possession")
+	}
+	if err := g1pubs.VerifyPossession(g1pubs.NewAggregatePubkey(), proof); !errors.Is(err, g1pubs.ErrIdentityKey) {
+		t.Fatalf("expected ErrIdentityKey, got %v", err)
+	}
+}
+
+func TestThresholdSignatures(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(80))
+	pub := g1pubs.PrivToPub(priv)
+	shares, err := g1pubs.SplitSecretKey(priv, 3, 5, NewXORShift(81))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("expected 5 shares, got %d", len(shares))
+	}
+
+	msg := []byte("threshold")
+	partials := make([]*g1pubs.PartialSignature, len(shares))
+	pubShares := make([]*g1pubs.PublicKeyShare, len(shares))
+	for i, s := range shares {
+		partials[i] = g1pubs.SignShare(msg, s)
+		pubShares[i] = s.PublicKeyShare()
+		if err := g1pubs.VerifyPartialSignature(msg, pubShares[i], partials[i]); err != nil {
+			t.Fatalf("partial signature %d: %v", i, err)
+		}
+	}
+	if err := g1pubs.VerifyPartialSignature(msg, pubShares[0], partials[1]); !errors.Is(err, g1pubs.ErrInvalidShareIndex) {
+		t.Fatalf("expected ErrInvalidShareIndex, got %v", err)
+	}
+	forged := &g1pubs.PartialSignature{Index: 1, Signature: partials[1].Signature}
+	if err := g1pubs.VerifyPartialSignature(msg, pubShares[0], forged); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	expected := g1pubs.Sign(msg, priv).Serialize()
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4, 0}} {
+		ps := make([]*g1pubs.PartialSignature, len(subset))
+		ss := make([]*g1pubs.SecretKeyShare, len(subset))
+		pks := make([]*g1pubs.PublicKeyShare, len(subset))
+		for i, j := range subset {
+			ps[i], ss[i], pks[i] = partials[j], shares[j], pubShares[j]
+		}
+		sig, err := g1pubs.RecoverSignature(ps, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig.Serialize() != expected {
+			t.Fatalf("signature recovered from %v does not match", subset)
+		}
+		if err := g1pubs.VerifyE(msg, pub, sig); err != nil {
+			t.Fatal(err)
+		}
+		key, err := g1pubs.RecoverSecretKey(ss, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key.Serialize() != priv.Serialize() {
+			t.Fatalf("secret key recovered from %v does not match", subset)
+		}
+		groupPub, err := g1pubs.RecoverPublicKey(pks, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !groupPub.Equals(*pub) {
+			t.Fatalf("public key recovered from %v does not match", subset)
+		}
+	}
+
+	sig, err := g1pubs.RecoverSignature(partials[:2], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g1pubs.Verify(msg, pub, sig) {
+		t.Fatal("signature recovered from fewer shares than the threshold verified")
+	}
+
+	if _, err := g1pubs.RecoverSignature(partials[:2], 3); !errors.Is(err, g1pubs.ErrNotEnoughShares) {
+		t.Fatalf("expected ErrNotEnoughShares, got %v", err)
+	}
+	dup := []*g1pubs.PartialSignature{partials[0], partials[1], partials[0]}
+	if _, err := g1pubs.RecoverSignature(dup, 3); !errors.Is(err, g1pubs.ErrDuplicateShareIndex) {
+		t.Fatalf("expected ErrDuplicateShareIndex, got %v", err)
+	}
+	zero := []*g1pubs.PartialSignature{partials[0], {Index: 0, Signature: partials[1].Signature}}
+	if _, err := g1pubs.RecoverSignature(zero, 2); !errors.Is(err, g1pubs.ErrInvalidShareIndex) {
+		t.Fatalf("expected ErrInvalidShareIndex, got %v", err)
+	}
+	for _, tn := range [][2]int{{0, 5}, {6, 5}, {-1, 5}} {
+		if _, err := g1pubs.SplitSecretKey(priv, tn[0], tn[1], NewXORShift(82)); !errors.Is(err, g1pubs.ErrInvalidThreshold) {
+			t.Fatalf("expected ErrInvalidThreshold for %d of %d, got %v", tn[0], tn[1], err)
+		}
+	}
+
+	single, err := g1pubs.SplitSecretKey(priv, 1, 2, NewXORShift(83))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single[0].Key.Serialize() != priv.Serialize() || single[1].Key.Serialize() != priv.Serialize() {
+		t.Fatal("shares with a threshold of one should be the key itself")
+	}
+}
+
+func TestBDNAggregation(t *testing.T) {
+	r := NewXORShift(90)
+	msg := []byte("multi-signature")
+	privs := make([]*g1pubs.SecretKey, 5)
+	pubs := make([]*g1pubs.PublicKey, len(privs))
+	sigs := make([]*g1pubs.Signature, len(privs))
+	for i := range privs {
+		privs[i], _ = g1pubs.RandKey(r)
+		pubs[i] = g1pubs.PrivToPub(privs[i])
+		sigs[i] = g1pubs.Sign(msg, privs[i])
+	}
+
+	agg, err := g1pubs.AggregateSignaturesBDN(sigs, pubs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := agg.VerifyAggregateCommonBDN(pubs, msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := g1pubs.VerifyE(msg, g1pubs.AggregatePublicKeysBDN(pubs), agg); err != nil {
+		t.Fatal(err)
+	}
+	if err := agg.VerifyAggregateCommonE(pubs, msg); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected a BDN signature to fail plain aggregate verification, got %v", err)
+	}
+	if err := agg.VerifyAggregateCommonBDN(pubs, []byte("other message")); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for another message, got %v", err)
+	}
+	if err := agg.VerifyAggregateCommonBDN(pubs[:4], msg); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for a missing key, got %v", err)
+	}
+
+	reversed := make([]*g1pubs.PublicKey, len(pubs))
+	reversedSigs := make([]*g1pubs.Signature, len(sigs))
+	for i := range pubs {
+		reversed[len(pubs)-1-i] = pubs[i]
+		reversedSigs[len(sigs)-1-i] = sigs[i]
+	}
+	if err := agg.VerifyAggregateCommonBDN(reversed, msg); err != nil {
+		t.Fatalf("verification should not depend on the order of the keys: %v", err)
+	}
+	agg2, _ := g1pubs.AggregateSignaturesBDN(reversedSigs, reversed)
+	if agg2.Serialize() != agg.Serialize() {
+		t.Fatal("aggregation should not depend on the order of the keys")
+	}
+
+	if _, err := g1pubs.AggregateSignaturesBDN(sigs[:4], pubs); !errors.Is(err, g1pubs.ErrSignatureCountMismatch) {
+		t.Fatalf("expected ErrSignatureCountMismatch, got %v", err)
+	}
+	withIdentity := append([]*g1pubs.PublicKey{g1pubs.NewAggregatePubkey()}, pubs...)
+	if err := agg.VerifyAggregateCommonBDN(withIdentity, msg); !errors.Is(err, g1pubs.ErrIdentityKey) {
+		t.Fatalf("expected ErrIdentityKey, got %v", err)
+	}
+}
+
+func TestBDNRogueKey(t *testing.T) {
+	r := NewXORShift(91)
+	msg := []byte("rogue")
+	victim, _ := g1pubs.RandKey(r)
+	victimPub := g1pubs.PrivToPub(victim)
+
+	// The attacker publishes x*g - victimPub and signs alone with x.
+	attacker, _ := g1pubs.RandKey(r)
+	neg := victimPub.GetPoint().ToAffine()
+	neg.NegAssign()
+	rogue := g1pubs.PrivToPub(attacker)
+	rogue.Aggregate(g1pubs.NewPublicKeyFromG1(neg))
+	forged := g1pubs.Sign(msg, attacker)
+
+	pubs := []*g1pubs.PublicKey{victimPub, rogue}
+	if err := forged.VerifyAggregateCommonE(pubs, msg); err != nil {
+		t.Fatalf("expected the rogue key attack to work on plain aggregation: %v", err)
+	}
+	if err := forged.VerifyAggregateCommonBDN(pubs, msg); !errors.Is(err, g1pubs.ErrInvalidSignature) {
+		t.Fatalf("expected ErrInvalidSignature for a rogue key, got %v", err)
+	}
+}
+
+func BenchmarkAggregatePublicKeysBDN(b *testing.B) {
+	priv, _ := g1pubs.RandKey(NewXORShift(92))
+	pub := g1pubs.PrivToPub(priv)
+	pubs := make([]*g1pubs.PublicKey, 1000)
+	acc := g1pubs.NewAggregatePubkey()
+	for i := range pubs {
+		acc.Aggregate(pub)
+		pubs[i] = acc.Copy()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g1pubs.AggregatePublicKeysBDN(pubs)
+	}
+}
+
+func TestVerifyIdentitySignature(t *testing.T) {
+	priv, _ := g1pubs.RandKey(NewXORShift(100))
+	pub := g1pubs.PrivToPub(priv)
+	msg := []byte("identity")
+
+	var b [96]byte
+	b[0] = 0xc0
+	sig, err := g1pubs.DeserializeSignature(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g1pubs.Verify(msg, pub, sig) {
+		t.Fatal("identity signature should not verify")
+	}
+	if err := g1pubs.VerifyE(msg, pub, sig); !errors.Is(err, g1pubs.ErrIdentitySignature) {
+		t.Fatalf("expected ErrIdentitySignature, got %v", err)
+	}
+	if err := g1pubs.VerifyWithDST(msg, pub, sig, []byte("DST")); !errors.Is(err, g1pubs.ErrIdentitySignature) {
+		t.Fatalf("expected ErrIdentitySignature from VerifyWithDST, got %v", err)
+	}
+	if err := g1pubs.VerifyPossession(pub, sig); !errors.Is(err, g1pubs.ErrIdentitySignature) {
+		t.Fatalf("expected ErrIdentitySignature from VerifyPossession, got %v", err)
+	}
+	if err := sig.VerifyAggregateE([]*g1pubs.PublicKey{pub}, [][]byte{msg}); !errors.Is(err, g1pubs.ErrIdentitySignature) {
+		t.Fatalf("expected ErrIdentitySignature from VerifyAggregateE, got %v", err)
+	}
+	share := &g1pubs.PublicKeyShare{Index: 1, Key: pub}
+	partial := &g1pubs.PartialSignature{Index: 1, Signature: sig}
+	if err := g1pubs.VerifyPartialSignature(msg, share, partial); !errors.Is(err, g1pubs.ErrIdentitySignature) {
+		t.Fatalf("expected ErrIdentitySignature from VerifyPartialSignature, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package g1pubs
+
+import (
+	"fmt"
+
+	"github.com/phoreproject/bls"
+)
+
+// MarshalBinary encodes the public key in compressed form.
+func (p PublicKey) MarshalBinary() ([]byte, error) {
+	b := p.Serialize()
+	return b[:], nil
+}
+
+// UnmarshalBinary decodes a compressed public key and checks that it
+// is in the correct subgroup.
+func (p *PublicKey) UnmarshalBinary(data []byte) error {
+	var a bls.G1Affine
+	if err := a.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	p.p = a.ToProjective()
+	return nil
+}
+
+// MarshalText encodes the compressed public key as 0x-prefixed hex.
+func (p PublicKey) MarshalText() ([]byte, error) {
+	b := p.Serialize()
+	return bls.EncodeHex(b[:]), nil
+}
+
+// UnmarshalText decodes a hex compressed public key and checks that it
+// is in the correct subgroup.
+func (p *PublicKey) UnmarshalText(text []byte) error {
+	var a bls.G1Affine
+	if err := a.UnmarshalText(text); err != nil {
+		return err
+	}
+	p.p = a.ToProjective()
+	return nil
+}
+
+// MarshalBinary encodes the signature in compressed form.
+func (s Signature) MarshalBinary() ([]byte, error) {
+	b := s.Serialize()
+	return b[:], nil
+}
+
+// UnmarshalBinary decodes a compressed signature and checks that it is
+// in the correct subgroup.
+func (s *Signature) UnmarshalBinary(data []byte) error {
+	var a bls.G2Affine
+	if err := a.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	s.s = a.ToProjective()
+	return nil
+}
+
+// MarshalText encodes the compressed signature as 0x-prefixed hex.
+func (s Signature) MarshalText() ([]byte, error) {
+	b := s.Serialize()
+	return bls.EncodeHex(b[:]), nil
+}
+
+// UnmarshalText decodes a hex compressed signature and checks that it
+// is in the correct subgroup.
+func (s *Signature) UnmarshalText(text []byte) error {
+	var a bls.G2Affine
+	if err := a.UnmarshalText(text); err != nil {
+		return err
+	}
+	s.s = a.ToProjective()
+	return nil
+}
+
+func secretKeyFromBytes(data []byte) (*SecretKey, error) {
+	if len(data) != 32 {
+		return nil, fmt.Errorf("%w: expected 32 bytes, got %d", ErrInvalidEncoding, len(data))
+	}
+	var b [32]byte
+	copy(b[:], data)
+	return DeserializeSecretKeyStrict(b)
+}
+
+// MarshalBinary encodes the secret key as 32 big-endian bytes.
+func (s SecretKey) MarshalBinary() ([]byte, error) {
+	b := s.Serialize()
+	return b[:], nil
+}
+
+// UnmarshalBinary decodes a secret key, rejecting zero and values that
+// are not less than the group order.
+func (s *SecretKey) UnmarshalBinary(data []byte) error {
+	k, err := secretKeyFromBytes(data)
+	if err != nil {
+		return err
+	}
+	*s = *k
+	return nil
+}
+
+// MarshalText encodes the secret key as 0x-prefixed hex.
+func (s SecretKey) MarshalText() ([]byte, error) {
+	b := s.Serialize()
+	return bls.EncodeHex(b[:]), nil
+}
+
+// UnmarshalText decodes a hex secret key, rejecting zero and values that
+// are not less than the group order.
+func (s *SecretKey) UnmarshalText(text []byte) error {
+	data, err := bls.DecodeHex(text, 32)
+	if err != nil {
+		return err
+	}
+	return s.UnmarshalBinary(data)
+}
@@ -0,0 +1,139 @@
+package g1pubs
+
+import (
+	"fmt"
+
+	"github.com/phoreproject/bls"
+)
+
+// JWSAlgorithm is the JWS alg value for signatures by keys in this
+// package. No JWS algorithm has been registered for BLS signatures, so
+// it is named after the group of the public key.
+const JWSAlgorithm = "BLS12381G1"
+
+// jwsDST separates JWS signatures from other signatures by the same key.
+var jwsDST = []byte("JWS_" + JWSAlgorithm + "_")
+
+// PublicKeyToJWK converts a public key to a JWK with the given key type,
+// bls.JWKKeyTypeOKP or bls.JWKKeyTypeEC.
+func PublicKeyToJWK(pub *PublicKey, kty string) (*bls.JWK, error) {
+	switch kty {
+	case bls.JWKKeyTypeOKP:
+		b := pub.Serialize()
+		return &bls.JWK{Kty: kty, Crv: bls.JWKCurveG1, X: bls.EncodeBase64URL(b[:])}, nil
+	case bls.JWKKeyTypeEC:
+		if pub.p.IsZero() {
+			return nil, ErrIdentityKey
+		}
+		b := pub.SerializeUncompressed()
+		return &bls.JWK{
+			Kty: kty,
+			Crv: bls.JWKCurveG1,
+			X:   bls.EncodeBase64URL(b[:48]),
+			Y:   bls.EncodeBase64URL(b[48:]),
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported JWK key type %q", ErrInvalidEncoding, kty)
+}
+
+// SecretKeyToJWK converts a secret key to a JWK with the given key type
+// that includes the public key and the private d member.
+func SecretKeyToJWK(key *SecretKey, kty string) (*bls.JWK, error) {
+	jwk, err := PublicKeyToJWK(PrivToPub(key), kty)
+	if err != nil {
+		return nil, err
+	}
+	d := key.Serialize()
+	defer zeroBytes(d[:])
+	jwk.D = bls.EncodeBase64URL(d[:])
+	return jwk, nil
+}
+
+// PublicKeyFromJWK converts a JWK to a public key and checks that it is
+// in the correct subgroup.
+func PublicKeyFromJWK(jwk *bls.JWK) (*PublicKey, error) {
+	if jwk.Crv != bls.JWKCurveG1 {
+		return nil, fmt.Errorf("%w: JWK curve %q", ErrUnexpectedAlgorithm, jwk.Crv)
+	}
+	switch jwk.Kty {
+	case bls.JWKKeyTypeOKP:
+		if jwk.Y != "" {
+			return nil, fmt.Errorf("%w: unexpected y member in OKP JWK", ErrInvalidEncoding)
+		}
+		x, err := bls.DecodeBase64URL(jwk.X, 48)
+		if err != nil {
+			return nil, err
+		}
+		pub := new(PublicKey)
+		if err := pub.UnmarshalBinary(x); err != nil {
+			return nil, err
+		}
+		return pub, nil
+	case bls.JWKKeyTypeEC:
+		x, err := bls.DecodeBase64URL(jwk.X, 48)
+		if err != nil {
+			return nil, err
+		}
+		y, err := bls.DecodeBase64URL(jwk.Y, 48)
+		if err != nil {
+			return nil, err
+		}
+		var b [96]byte
+		copy(b[:48], x)
+		copy(b[48:], y)
+		return DeserializePublicKeyUncompressed(b)
+	}
+	return nil, fmt.Errorf("%w: unsupported JWK key type %q", ErrInvalidEncoding, jwk.Kty)
+}
+
+// SecretKeyFromJWK converts a JWK with a private d member to a secret
+// key and checks that it matches the public key in the JWK.
+func SecretKeyFromJWK(jwk *bls.JWK) (*SecretKey, error) {
+	pub, err := PublicKeyFromJWK(jwk)
+	if err != nil {
+		return nil, err
+	}
+	d, err := bls.DecodeBase64URL(jwk.D, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(d)
+	key, err := secretKeyFromBytes(d)
+	if err != nil {
+		return nil, err
+	}
+	if !PrivToPub(key).Equal(pub) {
+		key.Zeroize()
+		return nil, fmt.Errorf("%w: JWK private key does not match public key", ErrInvalidEncoding)
+	}
+	return key, nil
+}
+
+// SignJWS signs the payload and returns a JWS in compact serialization
+// with the alg header set to JWSAlgorithm.
+func SignJWS(payload []byte, key *SecretKey) (string, error) {
+	return bls.SignJWSCompact(JWSAlgorithm, payload, func(signingInput []byte) ([]byte, error) {
+		sig, err := SignWithDST(signingInput, key, jwsDST)
+		if err != nil {
+			return nil, err
+		}
+		return sig.MarshalBinary()
+	})
+}
+
+// VerifyJWS verifies a JWS in compact serialization created by SignJWS
+// and returns its payload.
+func VerifyJWS(token string, pub *PublicKey) ([]byte, error) {
+	signingInput, payload, sigBytes, err := bls.ParseJWSCompact(token, JWSAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	var sig Signature
+	if err := sig.UnmarshalBinary(sigBytes); err != nil {
+		return nil, err
+	}
+	if err := VerifyWithDST(signingInput, pub, &sig, jwsDST); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
@@ -0,0 +1,31 @@
+package g1pubs
+
+// popDST is the proof of possession tag of the standard
+// BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_ ciphersuite. It separates
+// proofs of possession from signatures on messages that happen to be
+// serialized public keys.
+var popDST = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+// CiphersuitePOP is the signature tag of the standard
+// BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_ ciphersuite, which is the
+// ciphersuite of Ethereum validator signatures. Pass it to SignWithDST
+// and VerifyWithDST to create and check signatures of that ciphersuite,
+// whose public keys are checked with VerifyPossession.
+const CiphersuitePOP = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+
+// ProvePossession creates a proof of possession of the secret key by
+// signing the compressed public key with a separate domain separation
+// tag. Checking these proofs before aggregating public keys prevents
+// rogue key attacks.
+func ProvePossession(key *SecretKey) *Signature {
+	pub := PrivToPub(key).Serialize()
+	h := hashWithDST(pub[:], popDST).MulFR(keyRepr(key))
+	return &Signature{s: h}
+}
+
+// VerifyPossession verifies a proof of possession created by
+// ProvePossession. It rejects the identity public key.
+func VerifyPossession(pub *PublicKey, proof *Signature) error {
+	b := pub.Serialize()
+	return VerifyWithDST(b[:], pub, proof, popDST)
+}
@@ -0,0 +1,145 @@
+package g1pubs
+
+import (
+	"crypto"
+	"io"
+
+	"github.com/phoreproject/bls"
+)
+
+// SignerOpts are the options for Signer.Sign and PublicKey.Verify. The
+// domain separation tag selects the ciphersuite. An empty DST uses the
+// default ciphersuite of Sign and Verify.
+type SignerOpts struct {
+	DST []byte
+}
+
+// HashFunc returns zero because messages are hashed to the curve
+// rather than prehashed.
+func (o *SignerOpts) HashFunc() crypto.Hash {
+	return 0
+}
+
+// dstFromOpts gets the domain separation tag from signer options. Plain
+// crypto.SignerOpts are accepted as long as they do not ask for a
+// prehashed message.
+func dstFromOpts(opts crypto.SignerOpts) ([]byte, error) {
+	switch o := opts.(type) {
+	case nil:
+		return nil, nil
+	case *SignerOpts:
+		if o == nil {
+			return nil, nil
+		}
+		if len(o.DST) > bls.MaxDSTLength {
+			return nil, ErrDSTTooLong
+		}
+		return o.DST, nil
+	}
+	if opts.HashFunc() != 0 {
+		return nil, ErrUnsupportedSignerOpts
+	}
+	return nil, nil
+}
+
+func hashWithDST(message []byte, dst []byte) *bls.G2Affine {
+	if len(dst) == 0 {
+		return bls.HashG2(message)
+	}
+	return bls.HashG2WithDST(message, dst)
+}
+
+// SignWithDST signs a message with a secret key using a domain
+// separation tag. An empty tag is the same as Sign.
+func SignWithDST(message []byte, key *SecretKey, dst []byte) (*Signature, error) {
+	if len(dst) > bls.MaxDSTLength {
+		return nil, ErrDSTTooLong
+	}
+	h := hashWithDST(message, dst).MulFR(keyRepr(key))
+	return &Signature{s: h}, nil
+}
+
+// VerifyWithDST verifies a signature against a message and a public key
+// using a domain separation tag. Like VerifyE, it rejects the identity
+// public key.
+func VerifyWithDST(m []byte, pub *PublicKey, sig *Signature, dst []byte) error {
+	if len(dst) > bls.MaxDSTLength {
+		return ErrDSTTooLong
+	}
+	if pub.p.IsZero() {
+		return ErrIdentityKey
+	}
+	if sig.s.IsZero() {
+		return ErrIdentitySignature
+	}
+	h := hashWithDST(m, dst)
+	if !bls.CompareTwoPairings(bls.G1ProjectiveOne, sig.s, pub.p, h.ToProjective()) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Signer wraps a secret key to implement crypto.Signer. Signatures are
+// returned in compressed form.
+type Signer struct {
+	key *SecretKey
+	pub *PublicKey
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// NewSigner creates a signer for a secret key.
+func NewSigner(key *SecretKey) *Signer {
+	return &Signer{key: key, pub: PrivToPub(key)}
+}
+
+// Public returns the *PublicKey of the signer.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pub.Copy()
+}
+
+// Sign signs the message, which is not prehashed. The random source is
+// unused since BLS signatures are deterministic. opts may be nil, a
+// *SignerOpts or any crypto.SignerOpts with a zero HashFunc.
+func (s *Signer) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	dst, err := dstFromOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := SignWithDST(message, s.key, dst)
+	if err != nil {
+		return nil, err
+	}
+	return sig.MarshalBinary()
+}
+
+// Verifier verifies signatures created by a crypto.Signer.
+type Verifier interface {
+	Verify(message []byte, sig []byte, opts crypto.SignerOpts) error
+}
+
+var _ Verifier = (*PublicKey)(nil)
+
+// Verify verifies a compressed signature created by Signer.Sign with
+// the same options.
+func (p *PublicKey) Verify(message []byte, sig []byte, opts crypto.SignerOpts) error {
+	dst, err := dstFromOpts(opts)
+	if err != nil {
+		return err
+	}
+	var s Signature
+	if err := s.UnmarshalBinary(sig); err != nil {
+		return err
+	}
+	return VerifyWithDST(message, p, &s, dst)
+}
+
+// Equal checks if x is a *PublicKey with the same point, so public
+// keys satisfy the interface expected of crypto.PublicKey.
+func (p *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	if !ok || other == nil {
+		return false
+	}
+	return p.p.Equal(other.p)
+}
@@ -0,0 +1,191 @@
+package g1pubs
+
+import (
+	"io"
+	"math"
+
+	"github.com/phoreproject/bls"
+)
+
+// SecretKeyShare is a share of a secret key split by SplitSecretKey.
+// Index is the non-zero point the sharing polynomial was evaluated at.
+type SecretKeyShare struct {
+	Index uint32
+	Key   *SecretKey
+}
+
+// PublicKeyShare is the public key of a secret key share.
+type PublicKeyShare struct {
+	Index uint32
+	Key   *PublicKey
+}
+
+// PartialSignature is a signature made with a secret key share.
+type PartialSignature struct {
+	Index     uint32
+	Signature *Signature
+}
+
+// PublicKeyShare returns the public key of the share, which verifies the
+// partial signatures made with it.
+func (s *SecretKeyShare) PublicKeyShare() *PublicKeyShare {
+	return &PublicKeyShare{Index: s.Index, Key: PrivToPub(s.Key)}
+}
+
+// SplitSecretKey splits a secret key into n shares with indices 1 to n
+// using Shamir secret sharing. Any threshold of the shares can recover
+// the key or a signature made with it, and fewer reveal nothing about
+// it. The random polynomial coefficients are read from r.
+func SplitSecretKey(key *SecretKey, threshold int, n int, r io.Reader) ([]*SecretKeyShare, error) {
+	if threshold < 1 || threshold > n || uint64(n) > math.MaxUint32 {
+		return nil, ErrInvalidThreshold
+	}
+	coeffs := make([]bls.Scalar, threshold)
+	defer func() {
+		for i := range coeffs {
+			coeffs[i].Zeroize()
+		}
+	}()
+	coeffs[0] = key.f
+	for i := 1; i < threshold; i++ {
+		c, err := bls.RandScalar(r)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[i] = c
+	}
+
+	shares := make([]*SecretKeyShare, n)
+	for i := range shares {
+		index := uint32(i + 1)
+		x := indexScalar(index)
+		y := bls.ScalarZero.Copy()
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			y.MulAssign(x)
+			y.AddAssign(coeffs[j])
+		}
+		shares[i] = &SecretKeyShare{Index: index, Key: &SecretKey{f: y}}
+	}
+	return shares, nil
+}
+
+// SignShare signs a message with a secret key share.
+func SignShare(message []byte, share *SecretKeyShare) *PartialSignature {
+	return &PartialSignature{Index: share.Index, Signature: Sign(message, share.Key)}
+}
+
+// VerifyPartialSignature verifies a partial signature against the public
+// key of the share that made it.
+func VerifyPartialSignature(message []byte, pub *PublicKeyShare, partial *PartialSignature) error {
+	if pub.Index != partial.Index {
+		return ErrInvalidShareIndex
+	}
+	return VerifyE(message, pub.Key, partial.Signature)
+}
+
+func indexScalar(index uint32) bls.Scalar {
+	return bls.ScalarReprToScalar(*bls.NewFRRepr(uint64(index)))
+}
+
+// lagrangeCoefficients checks the indices of the first threshold shares
+// and returns their Lagrange coefficients for interpolating at zero.
+func lagrangeCoefficients(indices []uint32, threshold int) ([]bls.Scalar, error) {
+	if threshold < 1 {
+		return nil, ErrInvalidThreshold
+	}
+	if len(indices) < threshold {
+		return nil, ErrNotEnoughShares
+	}
+	indices = indices[:threshold]
+	xs := make([]bls.Scalar, threshold)
+	seen := make(map[uint32]bool, threshold)
+	for i, index := range indices {
+		if index == 0 {
+			return nil, ErrInvalidShareIndex
+		}
+		if seen[index] {
+			return nil, ErrDuplicateShareIndex
+		}
+		seen[index] = true
+		xs[i] = indexScalar(index)
+	}
+
+	// lambda_i = prod_{j != i} x_j / (x_j - x_i)
+	lambdas := make([]bls.Scalar, threshold)
+	for i := range xs {
+		num := bls.ScalarOne.Copy()
+		den := bls.ScalarOne.Copy()
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num.MulAssign(xs[j])
+			d := xs[j].Copy()
+			d.SubAssign(xs[i])
+			den.MulAssign(d)
+		}
+		inv, _ := den.Inverse()
+		num.MulAssign(inv)
+		lambdas[i] = num
+	}
+	return lambdas, nil
+}
+
+// RecoverSecretKey recovers a secret key from the first threshold of the
+// shares.
+func RecoverSecretKey(shares []*SecretKeyShare, threshold int) (*SecretKey, error) {
+	indices := make([]uint32, len(shares))
+	for i, s := range shares {
+		indices[i] = s.Index
+	}
+	lambdas, err := lagrangeCoefficients(indices, threshold)
+	if err != nil {
+		return nil, err
+	}
+	key := bls.ScalarZero.Copy()
+	for i, l := range lambdas {
+		l.MulAssign(shares[i].Key.f)
+		key.AddAssign(l)
+	}
+	return &SecretKey{f: key}, nil
+}
+
+// RecoverPublicKey recovers the public key of a split secret key from the
+// first threshold of the public key shares.
+func RecoverPublicKey(shares []*PublicKeyShare, threshold int) (*PublicKey, error) {
+	indices := make([]uint32, len(shares))
+	for i, s := range shares {
+		indices[i] = s.Index
+	}
+	lambdas, err := lagrangeCoefficients(indices, threshold)
+	if err != nil {
+		return nil, err
+	}
+	agg := bls.G1ProjectiveZero.Copy()
+	for i, l := range lambdas {
+		repr := l.ToRepr()
+		agg.AddAssign(shares[i].Key.p.MulFR(&repr))
+	}
+	return &PublicKey{p: agg}, nil
+}
+
+// RecoverSignature recovers the signature of the split secret key from
+// the first threshold of the partial signatures, interpolating them in
+// the exponent. The partial signatures should be verified first since
+// one invalid partial signature makes the result invalid.
+func RecoverSignature(partials []*PartialSignature, threshold int) (*Signature, error) {
+	indices := make([]uint32, len(partials))
+	for i, p := range partials {
+		indices[i] = p.Index
+	}
+	lambdas, err := lagrangeCoefficients(indices, threshold)
+	if err != nil {
+		return nil, err
+	}
+	agg := bls.G2ProjectiveZero.Copy()
+	for i, l := range lambdas {
+		repr := l.ToRepr()
+		agg.AddAssign(partials[i].Signature.s.MulFR(&repr))
+	}
+	return &Signature{s: agg}, nil
+}
@@ -2,7 +2,6 @@ package bls
 
 import (
 	"crypto/sha256"
-	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -80,9 +79,9 @@ func (g G2Affine) Mul(b FQRepr) *G2Projective {
 	res := G2ProjectiveZero.Copy()
 	for i := uint(0); i < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o {
-			res = res.AddAffine(&g)
+			res.AddAffineAssign(&g)
 		}
 	}
 	return res
@@ -93,9 +92,9 @@ func (g G2Affine) MulFR(b *FRRepr) *G2Projective {
 	res := G2ProjectiveZero.Copy()
 	for i := uint(0); i < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o {
-			res = res.AddAffine(&g)
+			res.AddAffineAssign(&g)
 		}
 	}
 	return res
@@ -106,9 +105,9 @@ func (g G2Affine) MulBig(b big.Int) *G2Projective {
 	res := G2ProjectiveZero.Copy()
 	for i := 0; i < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o == 1 {
-			res = res.AddAffine(&g)
+			res.AddAffineAssign(&g)
 		}
 	}
 	return res
@@ -155,7 +154,7 @@ func GetG2PointFromX(x FQ2, greatest bool) (*G2Affine, error) {
 	y, success := x3b.Sqrt()
 
 	if !success {
-		return nil, errors.New("point not on curve")
+		return nil, ErrNotOnCurve
 	}
 
 	negY := y.Copy()
@@ -223,7 +222,7 @@ func DecompressG2(c [96]byte) (*G2Affine, error) {
 	}
 
 	if !affine.IsInCorrectSubgroupAssumingOnCurve() {
-		return nil, errors.New("point is not in correct subgroup")
+		return nil, ErrNotInSubgroup
 	}
 	return affine, nil
 }
@@ -231,15 +230,18 @@ func DecompressG2(c [96]byte) (*G2Affine, error) {
 // DecompressG2Unchecked decompresses a G2 point from a big int.
 func DecompressG2Unchecked(c [96]byte) (*G2Affine, error) {
 	if c[0]&(1<<7) == 0 {
-		return nil, errors.New("unexpected compression mode")
+		return nil, ErrUnexpectedCompressionMode
 	}
 
 	if c[0]&(1<<6) != 0 {
+		if c[0]&(1<<5) != 0 {
+			return nil, ErrInfinitySortFlag
+		}
 		c[0] &= 0x3f
 
 		for _, b := range c {
 			if b != 0 {
-				return nil, errors.New("unexpected information in infinity point on G2")
+				return nil, ErrInfinityNotZero
 			}
 		}
 		return G2AffineZero.Copy(), nil
@@ -248,16 +250,18 @@ func DecompressG2Unchecked(c [96]byte) (*G2Affine, error) {
 
 	c[0] &= 0x1f
 
-	var xC0Bytes [48]byte
-	var xC1Bytes [48]byte
-
-	copy(xC1Bytes[:], c[:48])
-	copy(xC0Bytes[:], c[48:])
+	if c[48]&0xe0 != 0 {
+		return nil, ErrG2LimbFlags
+	}
 
-	xC0 := FQReprFromBytes(xC0Bytes)
-	xC0FQ := FQReprToFQ(xC0)
-	xC1 := FQReprFromBytes(xC1Bytes)
-	xC1FQ := FQReprToFQ(xC1)
+	xC1FQ, err := getFQ(c[:48])
+	if err != nil {
+		return nil, err
+	}
+	xC0FQ, err := getFQ(c[48:])
+	if err != nil {
+		return nil, err
+	}
 
 	x := NewFQ2(xC0FQ, xC1FQ)
 
@@ -288,6 +292,74 @@ func CompressG2(affine *G2Affine) [96]byte {
 	return res
 }
 
+// SerializeG2Uncompressed serializes a G2 point as
+// x.c1 || x.c0 || y.c1 || y.c0 with the ZCash flag bits in the first byte.
+func SerializeG2Uncompressed(affine *G2Affine) [192]byte {
+	res := [192]byte{}
+	if affine.IsZero() {
+		res[0] |= 1 << 6
+		return res
+	}
+	putFQ(res[0:48], affine.x.c1)
+	putFQ(res[48:96], affine.x.c0)
+	putFQ(res[96:144], affine.y.c1)
+	putFQ(res[144:192], affine.y.c0)
+	return res
+}
+
+// DeserializeG2Uncompressed deserializes an uncompressed G2 point and
+// checks that it is on the curve and in the correct subgroup.
+func DeserializeG2Uncompressed(b [192]byte) (*G2Affine, error) {
+	affine, err := DeserializeG2UncompressedUnchecked(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !affine.IsOnCurve() {
+		return nil, ErrNotOnCurve
+	}
+
+	if !affine.IsInCorrectSubgroupAssumingOnCurve() {
+		return nil, ErrNotInSubgroup
+	}
+	return affine, nil
+}
+
+// DeserializeG2UncompressedUnchecked deserializes an uncompressed G2 point
+// without checking that it is on the curve or in the correct subgroup.
+func DeserializeG2UncompressedUnchecked(b [192]byte) (*G2Affine, error) {
+	if b[0]&(1<<7) != 0 {
+		return nil, ErrUnexpectedCompressionMode
+	}
+
+	if b[0]&(1<<6) != 0 {
+		if b[0]&(1<<5) != 0 {
+			return nil, ErrInfinitySortFlag
+		}
+		b[0] &= 0x3f
+		for _, v := range b {
+			if v != 0 {
+				return nil, ErrInfinityNotZero
+			}
+		}
+		return G2AffineZero.Copy(), nil
+	}
+
+	if b[0]&(1<<5) != 0 {
+		return nil, ErrUnexpectedSortFlag
+	}
+
+	var c [4]FQ
+	for i := range c {
+		f, err := getFQ(b[48*i : 48*(i+1)])
+		if err != nil {
+			return nil, err
+		}
+		c[i] = f
+	}
+	return NewG2Affine(NewFQ2(c[1], c[0]), NewFQ2(c[3], c[2])), nil
+}
+
 // IsInCorrectSubgroupAssumingOnCurve checks if the point multiplied by the
 // field characteristic equals zero.
 func (g G2Affine) IsInCorrectSubgroupAssumingOnCurve() bool {
@@ -385,10 +457,15 @@ func (g G2Projective) ToAffine() *G2Affine {
 	return NewG2Affine(x, y)
 }
 
-// Double performs EC doubling on the point.
-func (g G2Projective) Double() *G2Projective {
+// Set sets the point equal to another point.
+func (g *G2Projective) Set(other *G2Projective) {
+	*g = *other
+}
+
+// DoubleAssign performs EC doubling on the point in place.
+func (g *G2Projective) DoubleAssign() {
 	if g.IsZero() {
-		return g.Copy()
+		return
 	}
 
 	// A = x1^2
@@ -421,34 +498,40 @@ func (g G2Projective) Double() *G2Projective {
 	f.SquareAssign()
 
 	// z3 = 2*Y1*Z1
-	newZ := g.z.Copy()
-	newZ.MulAssign(g.y)
-	newZ.DoubleAssign()
+	g.z.MulAssign(g.y)
+	g.z.DoubleAssign()
 
 	// x3 = F-2*D
-	newX := f.Copy()
-	newX.SubAssign(d)
-	newX.SubAssign(d)
+	g.x = f
+	g.x.SubAssign(d)
+	g.x.SubAssign(d)
 
 	c.DoubleAssign()
 	c.DoubleAssign()
 	c.DoubleAssign()
 
-	newY := d.Copy()
-	newY.SubAssign(newX)
-	newY.MulAssign(e)
-	newY.SubAssign(c)
+	// y3 = E*(D-x3)-8*C
+	g.y = d
+	g.y.SubAssign(g.x)
+	g.y.MulAssign(e)
+	g.y.SubAssign(c)
+}
 
-	return NewG2Projective(newX, newY, newZ)
+// Double performs EC doubling on the point.
+func (g G2Projective) Double() *G2Projective {
+	out := g.Copy()
+	out.DoubleAssign()
+	return out
 }
 
-// Add performs an EC Add operation with another point.
-func (g G2Projective) Add(other *G2Projective) *G2Projective {
+// AddAssign performs an EC Add operation with another point in place.
+func (g *G2Projective) AddAssign(other *G2Projective) {
 	if g.IsZero() {
-		return other.Copy()
+		g.Set(other)
+		return
 	}
 	if other.IsZero() {
-		return g.Copy()
+		return
 	}
 
 	// Z1Z1 = Z1^2
@@ -479,7 +562,8 @@ func (g G2Projective) Add(other *G2Projective) *G2Projective {
 
 	if u1.Equals(u2) && s1.Equals(s2) {
 		// points are equal
-		return g.Double()
+		g.DoubleAssign()
+		return
 	}
 
 	// H = U2-U1
@@ -496,15 +580,14 @@ func (g G2Projective) Add(other *G2Projective) *G2Projective {
 	j.MulAssign(i)
 
 	// r = 2*(S2-S1)
-	r := s2.Copy()
-	r.SubAssign(s1)
-	r.DoubleAssign()
+	s2.SubAssign(s1)
+	s2.DoubleAssign()
 
-	// v = U1*I
+	// U1 = U1*I
 	u1.MulAssign(i)
 
 	// X3 = r^2 - J - 2*V
-	newX := r.Copy()
+	newX := s2.Copy()
 	newX.SquareAssign()
 	newX.SubAssign(j)
 	newX.SubAssign(u1)
@@ -512,29 +595,49 @@ func (g G2Projective) Add(other *G2Projective) *G2Projective {
 
 	// Y3 = r*(V - X3) - 2*S1*J
 	u1.SubAssign(newX)
-	u1.MulAssign(r)
+	u1.MulAssign(s2)
 	s1.MulAssign(j)
 	s1.DoubleAssign()
 	u1.SubAssign(s1)
 
 	// Z3 = ((Z1+Z2)^2 - Z1Z1 - Z2Z2)*H
-	newZ := g.z.Copy()
-	newZ.AddAssign(other.z)
-	newZ.SquareAssign()
-	newZ.SubAssign(z1z1)
-	newZ.SubAssign(z2z2)
-	newZ.MulAssign(h)
-
-	return NewG2Projective(newX, u1, newZ)
+	g.z.AddAssign(other.z)
+	g.z.SquareAssign()
+	g.z.SubAssign(z1z1)
+	g.z.SubAssign(z2z2)
+	g.z.MulAssign(h)
+
+	g.x = newX
+	g.y = u1
 }
 
-// AddAffine performs an EC Add operation with an affine point.
-func (g G2Projective) AddAffine(other *G2Affine) *G2Projective {
-	if g.IsZero() {
-		return other.ToProjective()
+// Add performs an EC Add operation with another point.
+func (g G2Projective) Add(other *G2Projective) *G2Projective {
+	out := g.Copy()
+	out.AddAssign(other)
+	return out
+}
+
+// SetAdd sets the point to a + b. Either argument may be the receiver.
+func (g *G2Projective) SetAdd(a, b *G2Projective) {
+	if g == b {
+		g.AddAssign(a)
+		return
 	}
+	g.Set(a)
+	g.AddAssign(b)
+}
+
+// AddAffineAssign performs an EC Add operation with an affine point in place.
+func (g *G2Projective) AddAffineAssign(other *G2Affine) {
 	if other.IsZero() {
-		return g.Copy()
+		return
+	}
+	if g.IsZero() {
+		g.x = other.x
+		g.y = other.y
+		g.z = FQ2One
+		return
 	}
 
 	// Z1Z1 = Z1^2
@@ -552,7 +655,8 @@ func (g G2Projective) AddAffine(other *G2Affine) *G2Projective {
 
 	if g.x.Equals(u2) && g.y.Equals(s2) {
 		// points are equal
-		return g.Double()
+		g.DoubleAssign()
+		return
 	}
 
 	// H = U2-X1
@@ -596,13 +700,20 @@ func (g G2Projective) AddAffine(other *G2Affine) *G2Projective {
 	newY.SubAssign(i0)
 
 	// Z3 = (Z1+H)^2 - Z1Z1 - HH
-	newZ := g.z.Copy()
-	newZ.AddAssign(u2)
-	newZ.SquareAssign()
-	newZ.SubAssign(z1z1)
-	newZ.SubAssign(hh)
+	g.z.AddAssign(u2)
+	g.z.SquareAssign()
+	g.z.SubAssign(z1z1)
+	g.z.SubAssign(hh)
 
-	return NewG2Projective(newX, newY, newZ)
+	g.x = newX
+	g.y = newY
+}
+
+// AddAffine performs an EC Add operation with an affine point.
+func (g G2Projective) AddAffine(other *G2Affine) *G2Projective {
+	out := g.Copy()
+	out.AddAffineAssign(other)
+	return out
 }
 
 // Mul performs a EC multiply operation on the point.
@@ -610,25 +721,32 @@ func (g G2Projective) Mul(b FQRepr) *G2Projective {
 	res := G2ProjectiveZero.Copy()
 	for i := uint(0); i < uint(b.BitLen()); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		res.DoubleAssign()
 		if o {
-			res = res.Add(&g)
+			res.AddAssign(&g)
 		}
 	}
 	return res
 }
 
-// MulFR performs a EC multiply operation on the point.
-func (g G2Projective) MulFR(b *FRRepr) *G2Projective {
-	res := G2ProjectiveZero.Copy()
-	for i := uint(0); i < uint(b.BitLen()); i++ {
+// MulFRAssign performs a EC multiply operation on the point in place.
+func (g *G2Projective) MulFRAssign(b *FRRepr) {
+	base := *g
+	g.Set(G2ProjectiveZero)
+	for i := uint(0); i < b.BitLen(); i++ {
 		o := b.Bit(b.BitLen() - i - 1)
-		res = res.Double()
+		g.DoubleAssign()
 		if o {
-			res = res.Add(&g)
+			g.AddAssign(&base)
 		}
 	}
-	return res
+}
+
+// MulFR performs a EC multiply operation on the point.
+func (g G2Projective) MulFR(b *FRRepr) *G2Projective {
+	out := g.Copy()
+	out.MulFRAssign(b)
+	return out
 }
 
 var blsX, _ = FQReprFromString("d201000000010000", 16)
@@ -1,11 +1,195 @@
 package bls_test
 
 import (
+	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/phoreproject/bls"
 )
 
+func TestG2InPlaceMatchesAllocating(t *testing.T) {
+	r := NewXORShift(1)
+	for i := 0; i < 5; i++ {
+		a, _ := bls.RandG2(r)
+		b, _ := bls.RandG2(r)
+
+		sum := a.Copy()
+		sum.AddAssign(b)
+		if !sum.Equals(a.Add(b)) {
+			t.Fatal("AddAssign does not match Add")
+		}
+
+		sum.SetAdd(a, b)
+		if !sum.Equals(a.Add(b)) {
+			t.Fatal("SetAdd does not match Add")
+		}
+
+		double := a.Copy()
+		double.DoubleAssign()
+		if !double.Equals(a.Double()) {
+			t.Fatal("DoubleAssign does not match Double")
+		}
+
+		mixed := a.Copy()
+		mixed.AddAffineAssign(b.ToAffine())
+		if !mixed.Equals(a.Add(b)) {
+			t.Fatal("AddAffineAssign does not match Add")
+		}
+
+		f, _ := bls.RandFR(r)
+		product := a.Copy()
+		product.MulFRAssign(f.ToRepr())
+		if !product.Equals(a.ToAffine().MulFR(f.ToRepr())) {
+			t.Fatal("MulFRAssign does not match MulFR")
+		}
+	}
+}
+
+func TestG2InPlaceDoesNotAllocate(t *testing.T) {
+	a := bls.G2ProjectiveOne.Copy()
+	b := bls.G2ProjectiveOne.Double()
+	c := bls.G2AffineOne.Copy()
+	allocs := testing.AllocsPerRun(100, func() {
+		a.AddAssign(b)
+		a.DoubleAssign()
+		a.AddAffineAssign(c)
+		a.SetAdd(a, b)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected in-place operations not to allocate, got %f allocations", allocs)
+	}
+}
+
+func TestG2UncompressedGenerator(t *testing.T) {
+	expected := "13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e" +
+		"024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8" +
+		"0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79be" +
+		"0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801"
+	out := bls.SerializeG2Uncompressed(bls.G2AffineOne)
+	if hex.EncodeToString(out[:]) != expected {
+		t.Fatalf("unexpected uncompressed generator %x", out)
+	}
+
+	g, err := bls.DeserializeG2Uncompressed(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equals(bls.G2AffineOne) {
+		t.Fatal("generator did not round trip")
+	}
+}
+
+func TestG2UncompressedRoundTrip(t *testing.T) {
+	r := NewXORShift(1)
+	for i := 0; i < 5; i++ {
+		p, _ := bls.RandG2(r)
+		a := p.ToAffine()
+		a2, err := bls.DeserializeG2Uncompressed(bls.SerializeG2Uncompressed(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a2.Equals(a) {
+			t.Fatal("point did not round trip")
+		}
+	}
+
+	a, err := bls.DeserializeG2Uncompressed(bls.SerializeG2Uncompressed(bls.G2AffineZero))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsZero() {
+		t.Fatal("infinity did not round trip")
+	}
+}
+
+func TestG2UncompressedInvalid(t *testing.T) {
+	valid := bls.SerializeG2Uncompressed(bls.G2AffineOne)
+
+	compressed := valid
+	compressed[0] |= 0x80
+	if _, err := bls.DeserializeG2Uncompressed(compressed); err == nil {
+		t.Fatal("expected error with compression flag set")
+	}
+
+	sorted := valid
+	sorted[0] |= 0x20
+	if _, err := bls.DeserializeG2Uncompressed(sorted); err == nil {
+		t.Fatal("expected error with sort flag set")
+	}
+
+	inf := bls.SerializeG2Uncompressed(bls.G2AffineZero)
+	inf[191] = 1
+	if _, err := bls.DeserializeG2Uncompressed(inf); err == nil {
+		t.Fatal("expected error with data in infinity")
+	}
+
+	offCurve := valid
+	offCurve[191] ^= 1
+	if _, err := bls.DeserializeG2Uncompressed(offCurve); err == nil {
+		t.Fatal("expected error for point not on curve")
+	}
+	if _, err := bls.DeserializeG2UncompressedUnchecked(offCurve); err != nil {
+		t.Fatal("unchecked deserialization should not check the curve equation")
+	}
+
+	tooLarge := valid
+	modulus := bls.QFieldModulus.Bytes()
+	copy(tooLarge[48:96], modulus[:])
+	if _, err := bls.DeserializeG2UncompressedUnchecked(tooLarge); err == nil {
+		t.Fatal("expected error for coordinate not less than the modulus")
+	}
+}
+
+func TestDecompressG2NonCanonical(t *testing.T) {
+	valid := bls.CompressG2(bls.G2AffineOne)
+
+	notCompressed := valid
+	notCompressed[0] &= 0x7f
+	if _, err := bls.DecompressG2(notCompressed); !errors.Is(err, bls.ErrUnexpectedCompressionMode) {
+		t.Fatalf("expected ErrUnexpectedCompressionMode, got %v", err)
+	}
+
+	var infinitySorted [96]byte
+	infinitySorted[0] = 0xe0
+	if _, err := bls.DecompressG2(infinitySorted); !errors.Is(err, bls.ErrInfinitySortFlag) {
+		t.Fatalf("expected ErrInfinitySortFlag, got %v", err)
+	}
+
+	infinityData := bls.CompressG2(bls.G2AffineZero)
+	infinityData[95] = 1
+	if _, err := bls.DecompressG2(infinityData); !errors.Is(err, bls.ErrInfinityNotZero) {
+		t.Fatalf("expected ErrInfinityNotZero, got %v", err)
+	}
+
+	for _, flag := range []byte{0x80, 0x40, 0x20} {
+		limbFlags := valid
+		limbFlags[48] |= flag
+		if _, err := bls.DecompressG2Unchecked(limbFlags); !errors.Is(err, bls.ErrG2LimbFlags) {
+			t.Fatalf("expected ErrG2LimbFlags, got %v", err)
+		}
+	}
+
+	modulus := bls.QFieldModulus.Bytes()
+
+	c1TooLarge := valid
+	copy(c1TooLarge[:48], modulus[:])
+	c1TooLarge[0] |= 0x80
+	if _, err := bls.DecompressG2Unchecked(c1TooLarge); !errors.Is(err, bls.ErrNonCanonicalCoordinate) {
+		t.Fatalf("expected ErrNonCanonicalCoordinate, got %v", err)
+	}
+
+	c0TooLarge := valid
+	copy(c0TooLarge[48:], modulus[:])
+	if _, err := bls.DecompressG2Unchecked(c0TooLarge); !errors.Is(err, bls.ErrNonCanonicalCoordinate) {
+		t.Fatalf("expected ErrNonCanonicalCoordinate, got %v", err)
+	}
+
+	if _, err := bls.DecompressG2(valid); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func BenchmarkG2MulAssign(b *testing.B) {
 	type mulData struct {
 		g *bls.G2Projective
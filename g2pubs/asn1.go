@@ -0,0 +1,176 @@
+package g2pubs
+
+import (
+	"encoding/pem"
+	"fmt"
+
+	"github.com/phoreproject/bls"
+)
+
+const (
+	publicKeyPEMType = "PUBLIC KEY"
+	secretKeyPEMType = "PRIVATE KEY"
+)
+
+// oidKey identifies keys whose public keys are in G2 and whose signatures are in G1. It is under the arc of
+// this project described in the bls package.
+var oidKey = mustParseOID("2.25.209824747550876313493300493992439978690.2")
+
+func mustParseOID(s string) bls.OID {
+	oid, err := bls.ParseOID(s)
+	if err != nil {
+		panic(err)
+	}
+	return oid
+}
+
+// MarshalPKIXPublicKey encodes the compressed public key as a DER
+// SubjectPublicKeyInfo with the default object identifier of this
+// package.
+func MarshalPKIXPublicKey(pub *PublicKey) ([]byte, error) {
+	return MarshalPKIXPublicKeyWithOID(pub, oidKey)
+}
+
+// MarshalPKIXPublicKeyWithOID encodes the compressed public key as a DER
+// SubjectPublicKeyInfo identified by oid.
+func MarshalPKIXPublicKeyWithOID(pub *PublicKey, oid bls.OID) ([]byte, error) {
+	b := pub.Serialize()
+	return bls.MarshalSPKI(oid, b[:])
+}
+
+// ParsePKIXPublicKey decodes a DER SubjectPublicKeyInfo with the default
+// object identifier of this package and checks that the key is in the
+// correct subgroup.
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	return ParsePKIXPublicKeyWithOID(der, oidKey)
+}
+
+// ParsePKIXPublicKeyWithOID decodes a DER SubjectPublicKeyInfo
+// identified by oid and checks that the key is in the correct subgroup.
+func ParsePKIXPublicKeyWithOID(der []byte, oid bls.OID) (*PublicKey, error) {
+	b, err := bls.ParseSPKI(der, oid)
+	if err != nil {
+		return nil, err
+	}
+	pub := new(PublicKey)
+	if err := pub.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// MarshalPKCS8SecretKey encodes the secret key as a DER PKCS #8
+// PrivateKeyInfo with the default object identifier of this package.
+func MarshalPKCS8SecretKey(key *SecretKey) ([]byte, error) {
+	return MarshalPKCS8SecretKeyWithOID(key, oidKey)
+}
+
+// MarshalPKCS8SecretKeyWithOID encodes the secret key as a DER PKCS #8
+// PrivateKeyInfo identified by oid.
+func MarshalPKCS8SecretKeyWithOID(key *SecretKey, oid bls.OID) ([]byte, error) {
+	b := key.Serialize()
+	defer zeroBytes(b[:])
+	return bls.MarshalPKCS8(oid, b[:])
+}
+
+// ParsePKCS8SecretKey decodes a DER PKCS #8 PrivateKeyInfo with the
+// default object identifier of this package, rejecting zero and out of
+// range keys.
+func ParsePKCS8SecretKey(der []byte) (*SecretKey, error) {
+	return ParsePKCS8SecretKeyWithOID(der, oidKey)
+}
+
+// ParsePKCS8SecretKeyWithOID decodes a DER PKCS #8 PrivateKeyInfo
+// identified by oid, rejecting zero and out of range keys.
+func ParsePKCS8SecretKeyWithOID(der []byte, oid bls.OID) (*SecretKey, error) {
+	b, err := bls.ParsePKCS8(der, oid)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(b)
+	return secretKeyFromBytes(b)
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+func decodePEM(data []byte, blockType string) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidEncoding)
+	}
+	if block.Type != blockType {
+		return nil, fmt.Errorf("%w: unexpected PEM block type %q", ErrInvalidEncoding, block.Type)
+	}
+	return block.Bytes, nil
+}
+
+// EncodePublicKeyPEM encodes the public key as a "PUBLIC KEY" PEM block
+// with the default object identifier of this package.
+func EncodePublicKeyPEM(pub *PublicKey) ([]byte, error) {
+	return EncodePublicKeyPEMWithOID(pub, oidKey)
+}
+
+// EncodePublicKeyPEMWithOID encodes the public key as a "PUBLIC KEY" PEM
+// block holding a SubjectPublicKeyInfo identified by oid.
+func EncodePublicKeyPEMWithOID(pub *PublicKey, oid bls.OID) ([]byte, error) {
+	der, err := MarshalPKIXPublicKeyWithOID(pub, oid)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMType, Bytes: der}), nil
+}
+
+// DecodePublicKeyPEM decodes the first PEM block, which must be a
+// "PUBLIC KEY" block with the default object identifier of this package.
+func DecodePublicKeyPEM(data []byte) (*PublicKey, error) {
+	return DecodePublicKeyPEMWithOID(data, oidKey)
+}
+
+// DecodePublicKeyPEMWithOID decodes the first PEM block, which must be a
+// "PUBLIC KEY" block identified by oid.
+func DecodePublicKeyPEMWithOID(data []byte, oid bls.OID) (*PublicKey, error) {
+	der, err := decodePEM(data, publicKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePKIXPublicKeyWithOID(der, oid)
+}
+
+// EncodeSecretKeyPEM encodes the secret key as a "PRIVATE KEY" PEM block
+// with the default object identifier of this package.
+func EncodeSecretKeyPEM(key *SecretKey) ([]byte, error) {
+	return EncodeSecretKeyPEMWithOID(key, oidKey)
+}
+
+// EncodeSecretKeyPEMWithOID encodes the secret key as a "PRIVATE KEY" PEM
+// block holding a PrivateKeyInfo identified by oid.
+func EncodeSecretKeyPEMWithOID(key *SecretKey, oid bls.OID) ([]byte, error) {
+	der, err := MarshalPKCS8SecretKeyWithOID(key, oid)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(der)
+	return pem.EncodeToMemory(&pem.Block{Type: secretKeyPEMType, Bytes: der}), nil
+}
+
+// DecodeSecretKeyPEM decodes the first PEM block, which must be a
+// "PRIVATE KEY" block with the default object identifier of this
+// package.
+func DecodeSecretKeyPEM(data []byte) (*SecretKey, error) {
+	return DecodeSecretKeyPEMWithOID(data, oidKey)
+}
+
+// DecodeSecretKeyPEMWithOID decodes the first PEM block, which must be a
+// "PRIVATE KEY" block identified by oid.
+func DecodeSecretKeyPEMWithOID(data []byte, oid bls.OID) (*SecretKey, error) {
+	der, err := decodePEM(data, secretKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(der)
+	return ParsePKCS8SecretKeyWithOID(der, oid)
+}
@@ -0,0 +1,82 @@
+package g2pubs
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+
+	"github.com/phoreproject/bls"
+)
+
+// bdnDST separates the hashes of BDN coefficients from other uses of the
+// hash functions.
+var bdnDST = []byte("BLS_BDN_COEFFICIENTS_")
+
+var rModulus = bls.RFieldModulus.ToBig()
+
+// bdnCoefficients derives the coefficient of each public key from the
+// key and the set of all of the keys. The set is hashed in sorted order
+// so the coefficients do not depend on the order of the keys.
+func bdnCoefficients(pubKeys []*PublicKey) []*bls.FRRepr {
+	serialized := make([][]byte, len(pubKeys))
+	for i, p := range pubKeys {
+		b := p.Serialize()
+		serialized[i] = b[:]
+	}
+	sorted := sortByteArrays(append([][]byte(nil), serialized...))
+	h := sha256.New()
+	h.Write(bdnDST)
+	for _, b := range sorted {
+		h.Write(b)
+	}
+	set := h.Sum(nil)
+
+	coeffs := make([]*bls.FRRepr, len(pubKeys))
+	n := new(big.Int)
+	for i, b := range serialized {
+		h := sha512.New()
+		h.Write(bdnDST)
+		h.Write(set)
+		h.Write(b)
+		n.SetBytes(h.Sum(nil))
+		n.Mod(n, rModulus)
+		coeffs[i], _ = bls.FRReprFromBigInt(n)
+	}
+	return coeffs
+}
+
+// AggregatePublicKeysBDN aggregates public keys for verifying a
+// multi-signature without proofs of possession. Each key is weighted by
+// a coefficient hashed from it and the set of keys, as in the scheme of
+// Boneh, Drijvers and Neven, which prevents rogue key attacks.
+func AggregatePublicKeysBDN(pubKeys []*PublicKey) *PublicKey {
+	points := make([]*bls.G2Projective, len(pubKeys))
+	for i, p := range pubKeys {
+		points[i] = p.p
+	}
+	return &PublicKey{p: bls.MultiScalarMulG2(points, bdnCoefficients(pubKeys))}
+}
+
+// AggregateSignaturesBDN aggregates signatures of a message by the public
+// keys, given in the same order, weighting them like
+// AggregatePublicKeysBDN.
+func AggregateSignaturesBDN(sigs []*Signature, pubKeys []*PublicKey) (*Signature, error) {
+	if len(sigs) != len(pubKeys) {
+		return nil, ErrSignatureCountMismatch
+	}
+	points := make([]*bls.G1Projective, len(sigs))
+	for i, s := range sigs {
+		points[i] = s.s
+	}
+	return &Signature{s: bls.MultiScalarMulG1(points, bdnCoefficients(pubKeys))}, nil
+}
+
+// VerifyAggregateCommonBDN verifies a signature aggregated by
+// AggregateSignaturesBDN against the public keys and the message. Unlike
+// VerifyAggregateCommon, the keys do not need proofs of possession.
+func (s *Signature) VerifyAggregateCommonBDN(pubKeys []*PublicKey, msg []byte) error {
+	if err := checkIdentityKeys(pubKeys); err != nil {
+		return err
+	}
+	return VerifyE(msg, AggregatePublicKeysBDN(pubKeys), s)
+}
@@ -2,6 +2,7 @@ package g2pubs
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"log"
 	"sort"
@@ -39,6 +40,22 @@ func DeserializeSignature(b [48]byte) (*Signature, error) {
 	return &Signature{s: a.ToProjective()}, nil
 }
 
+// SerializeUncompressed serializes a signature in uncompressed form.
+func (s *Signature) SerializeUncompressed() [96]byte {
+	return bls.SerializeG1Uncompressed(s.s.ToAffine())
+}
+
+// DeserializeSignatureUncompressed deserializes an uncompressed
+// signature from bytes.
+func DeserializeSignatureUncompressed(b [96]byte) (*Signature, error) {
+	a, err := bls.DeserializeG1Uncompressed(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signature{s: a.ToProjective()}, nil
+}
+
 // Copy returns a copy of the signature.
 func (s *Signature) Copy() *Signature {
 	return &Signature{s.s.Copy()}
@@ -97,18 +114,61 @@ func DeserializePublicKey(b [96]byte) (*PublicKey, error) {
 	return &PublicKey{p: a.ToProjective()}, nil
 }
 
+// SerializeUncompressed serializes a public key to bytes in
+// uncompressed form.
+func (p PublicKey) SerializeUncompressed() [192]byte {
+	return bls.SerializeG2Uncompressed(p.p.ToAffine())
+}
+
+// DeserializePublicKeyUncompressed deserializes an uncompressed
+// public key from bytes.
+func DeserializePublicKeyUncompressed(b [192]byte) (*PublicKey, error) {
+	a, err := bls.DeserializeG2Uncompressed(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKey{p: a.ToProjective()}, nil
+}
+
 // SecretKey represents a BLS private key.
 type SecretKey struct {
-	f *bls.FR
+	f bls.Scalar
 }
 
-// GetFRElement gets the underlying FR element.
+// GetFRElement gets a copy of the underlying element as an FR.
 func (s SecretKey) GetFRElement() *bls.FR {
+	return s.f.ToFR()
+}
+
+// GetScalar gets the underlying scalar.
+func (s SecretKey) GetScalar() bls.Scalar {
 	return s.f
 }
 
+// String returns a redacted description of the secret key. Use Reveal
+// to get the key material.
 func (s SecretKey) String() string {
-	return s.f.String()
+	return "SecretKey(redacted)"
+}
+
+// Format implements fmt.Formatter so that every verb, including %x and
+// %#v, prints the redacted description.
+func (s SecretKey) Format(f fmt.State, verb rune) {
+	io.WriteString(f, s.String())
+}
+
+// Reveal returns the secret key as 0x-prefixed hex. It is intended for
+// deliberate export of key material only.
+func (s SecretKey) Reveal() string {
+	b := s.Serialize()
+	return string(bls.EncodeHex(b[:]))
+}
+
+// Zeroize overwrites the secret key with zeros. Copies of the key made
+// earlier, for example by value receivers or GetScalar, are not affected.
+func (s *SecretKey) Zeroize() {
+	s.f.Zeroize()
 }
 
 // Serialize serializes a secret key to bytes.
@@ -116,10 +176,28 @@ func (s SecretKey) Serialize() [32]byte {
 	return s.f.Bytes()
 }
 
-// DeserializeSecretKey deserializes a secret key from
-// bytes.
+// DeserializeSecretKey deserializes a secret key from bytes. It returns
+// nil if the bytes are zero or not less than the group order; use
+// DeserializeSecretKeyStrict to learn why.
 func DeserializeSecretKey(b [32]byte) *SecretKey {
-	return &SecretKey{bls.FRReprToFR(bls.FRReprFromBytes(b))}
+	k, err := DeserializeSecretKeyStrict(b)
+	if err != nil {
+		return nil
+	}
+	return k
+}
+
+// DeserializeSecretKeyStrict deserializes a secret key from bytes,
+// rejecting zero and values that are not less than the group order.
+func DeserializeSecretKeyStrict(b [32]byte) (*SecretKey, error) {
+	repr := bls.FRReprFromBytes(b)
+	if repr.Cmp(bls.RFieldModulus) >= 0 {
+		return nil, ErrSecretKeyOutOfRange
+	}
+	if repr.IsZero() {
+		return nil, ErrZeroSecretKey
+	}
+	return &SecretKey{bls.ScalarReprToScalar(*repr)}, nil
 }
 
 // DeriveSecretKey derives a secret key from
@@ -130,18 +208,28 @@ func DeriveSecretKey(b [32]byte) *SecretKey {
 
 // Sign signs a message with a secret key.
 func Sign(message []byte, key *SecretKey) *Signature {
-	h := bls.HashG1(message).MulFR(key.f.ToRepr())
+	h := bls.HashG1(message).MulFR(keyRepr(key))
 	return &Signature{s: h}
 }
 
+// NewSecretKeyFromScalar creates a secret key from a scalar.
+func NewSecretKeyFromScalar(f bls.Scalar) *SecretKey {
+	return &SecretKey{f: f}
+}
+
+func keyRepr(k *SecretKey) *bls.FRRepr {
+	r := k.f.ToRepr()
+	return &r
+}
+
 // PrivToPub converts the private key into a public key.
 func PrivToPub(k *SecretKey) *PublicKey {
-	return &PublicKey{p: bls.G2AffineOne.MulFR(k.f.ToRepr())}
+	return &PublicKey{p: bls.G2AffineOne.MulFR(keyRepr(k))}
 }
 
-// RandKey generates a random secret key.
+// RandKey generates a uniformly random non-zero secret key.
 func RandKey(r io.Reader) (*SecretKey, error) {
-	k, err := bls.RandFR(r)
+	k, err := bls.RandScalarNonZero(r)
 	if err != nil {
 		return nil, err
 	}
@@ -150,9 +238,10 @@ func RandKey(r io.Reader) (*SecretKey, error) {
 }
 
 // KeyFromFQRepr returns a new key based on a FQRepr in
-// FR.
+// FR. It returns nil if the value is zero or not less than the group
+// order.
 func KeyFromFQRepr(i *bls.FRRepr) *SecretKey {
-	return &SecretKey{f: bls.FRReprToFR(i)}
+	return DeserializeSecretKey(i.Bytes())
 }
 
 // Verify verifies a signature against a message and a public key.
@@ -161,34 +250,50 @@ func Verify(m []byte, pub *PublicKey, sig *Signature) bool {
 	return bls.CompareTwoPairings(sig.s, bls.G2ProjectiveOne, h.ToProjective(), pub.p)
 }
 
+// VerifyE verifies a signature against a message and a public key,
+// returning an error that describes why verification failed. Unlike
+// Verify, it rejects the identity public key and signature.
+func VerifyE(m []byte, pub *PublicKey, sig *Signature) error {
+	if pub.p.IsZero() {
+		return ErrIdentityKey
+	}
+	if sig.s.IsZero() {
+		return ErrIdentitySignature
+	}
+	if !Verify(m, pub, sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
 // AggregateSignatures adds up all of the signatures.
 func AggregateSignatures(s []*Signature) *Signature {
-	newSig := &Signature{s: bls.G1ProjectiveZero.Copy()}
+	agg := bls.G1ProjectiveZero.Copy()
 	for _, sig := range s {
-		newSig.Aggregate(sig)
+		agg.AddAssign(sig.s)
 	}
-	return newSig
+	return &Signature{s: agg}
 }
 
 // Aggregate adds one signature to another
 func (s *Signature) Aggregate(other *Signature) {
-	newS := s.s.Add(other.s)
-	s.s = newS
+	s.s.AddAssign(other.s)
 }
 
-// AggregatePublicKeys adds public keys together.
+// AggregatePublicKeys adds public keys together. This is only safe if
+// each key has a proof of possession; otherwise use
+// AggregatePublicKeysBDN.
 func AggregatePublicKeys(p []*PublicKey) *PublicKey {
-	newPub := &PublicKey{p: bls.G2ProjectiveZero.Copy()}
+	agg := bls.G2ProjectiveZero.Copy()
 	for _, pub := range p {
-		newPub.Aggregate(pub)
+		agg.AddAssign(pub.p)
 	}
-	return newPub
+	return &PublicKey{p: agg}
 }
 
 // Aggregate adds two public keys together.
 func (p *PublicKey) Aggregate(other *PublicKey) {
-	newP := p.p.Add(other.p)
-	p.p = newP
+	p.p.AddAssign(other.p)
 }
 
 // Copy copies the public key and returns it.
@@ -236,10 +341,9 @@ func sortByteArrays(src [][]byte) [][]byte {
 	return sorted
 }
 
-// VerifyAggregate verifies each public key against each message.
-func (s *Signature) VerifyAggregate(pubKeys []*PublicKey, msgs [][]byte) bool {
+func checkAggregateMessages(pubKeys []*PublicKey, msgs [][]byte) error {
 	if len(pubKeys) != len(msgs) {
-		return false
+		return ErrMessageCountMismatch
 	}
 
 	msgsCopy := make([][]byte, len(msgs))
@@ -255,11 +359,31 @@ func (s *Signature) VerifyAggregate(pubKeys []*PublicKey, msgs [][]byte) bool {
 	// check for duplicates
 	for _, m := range msgsSorted {
 		if bytes.Equal(m, lastMsg) {
-			return false
+			return ErrDuplicateMessage
 		}
 		lastMsg = m
 	}
+	return nil
+}
+
+func checkIdentityKeys(pubKeys []*PublicKey) error {
+	for _, p := range pubKeys {
+		if p.p.IsZero() {
+			return ErrIdentityKey
+		}
+	}
+	return nil
+}
+
+// VerifyAggregate verifies each public key against each message.
+func (s *Signature) VerifyAggregate(pubKeys []*PublicKey, msgs [][]byte) bool {
+	if checkAggregateMessages(pubKeys, msgs) != nil {
+		return false
+	}
+	return s.verifyAggregatePairing(pubKeys, msgs)
+}
 
+func (s *Signature) verifyAggregatePairing(pubKeys []*PublicKey, msgs [][]byte) bool {
 	lhs := bls.Pairing(s.s, bls.G2ProjectiveOne)
 	rhs := bls.FQ12One.Copy()
 	for i := range pubKeys {
@@ -276,3 +400,31 @@ func (s *Signature) VerifyAggregateCommon(pubKeys []*PublicKey, msg []byte) bool
 	aggPub := AggregatePublicKeys(pubKeys)
 	return Verify(msg, aggPub, s)
 }
+
+// VerifyAggregateE verifies each public key against each message,
+// returning an error that describes why verification failed.
+func (s *Signature) VerifyAggregateE(pubKeys []*PublicKey, msgs [][]byte) error {
+	if err := checkAggregateMessages(pubKeys, msgs); err != nil {
+		return err
+	}
+	if err := checkIdentityKeys(pubKeys); err != nil {
+		return err
+	}
+	if s.s.IsZero() {
+		return ErrIdentitySignature
+	}
+	if !s.verifyAggregatePairing(pubKeys, msgs) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyAggregateCommonE verifies each public key against a message,
+// returning an error that describes why verification failed. Like
+// VerifyAggregateCommon, each public key needs a proof-of-knowledge.
+func (s *Signature) VerifyAggregateCommonE(pubKeys []*PublicKey, msg []byte) error {
+	if err := checkIdentityKeys(pubKeys); err != nil {
+		return err
+	}
+	return VerifyE(msg, AggregatePublicKeys(pubKeys), s)
+}
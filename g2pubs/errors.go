@@ -0,0 +1,74 @@
+package g2pubs
+
+import "github.com/phoreproject/bls"
+
+// Errors returned when decoding keys and signatures or verifying
+// signatures. They are the same values as in the bls package so
+// errors.Is works with either.
+var (
+	// ErrInvalidEncoding is returned when bytes are not a valid encoding.
+	ErrInvalidEncoding = bls.ErrInvalidEncoding
+
+	// ErrNotOnCurve is returned when a point is not on the curve.
+	ErrNotOnCurve = bls.ErrNotOnCurve
+
+	// ErrNotInSubgroup is returned when a point is not in the prime order
+	// subgroup.
+	ErrNotInSubgroup = bls.ErrNotInSubgroup
+
+	// ErrIdentityKey is returned when a public key is the identity.
+	ErrIdentityKey = bls.ErrIdentityKey
+
+	// ErrIdentitySignature is returned when a signature is the identity.
+	ErrIdentitySignature = bls.ErrIdentitySignature
+
+	// ErrMessageCountMismatch is returned when the number of public keys
+	// and messages differ.
+	ErrMessageCountMismatch = bls.ErrMessageCountMismatch
+
+	// ErrDuplicateMessage is returned when an aggregate contains the same
+	// message twice.
+	ErrDuplicateMessage = bls.ErrDuplicateMessage
+
+	// ErrInvalidSignature is returned when a signature does not verify.
+	ErrInvalidSignature = bls.ErrInvalidSignature
+
+	// ErrZeroSecretKey is returned when a secret key is zero.
+	ErrZeroSecretKey = bls.ErrZeroSecretKey
+
+	// ErrSecretKeyOutOfRange is returned when a secret key is not less
+	// than the group order.
+	ErrSecretKeyOutOfRange = bls.ErrSecretKeyOutOfRange
+
+	// ErrDSTTooLong is returned when a domain separation tag is longer
+	// than bls.MaxDSTLength.
+	ErrDSTTooLong = bls.ErrDSTTooLong
+
+	// ErrUnsupportedSignerOpts is returned when a Signer is given options
+	// that request prehashing or are of an unknown type.
+	ErrUnsupportedSignerOpts = bls.ErrUnsupportedSignerOpts
+
+	// ErrUnexpectedAlgorithm is returned when an encoded key is for a
+	// different algorithm than expected.
+	ErrUnexpectedAlgorithm = bls.ErrUnexpectedAlgorithm
+
+	// ErrInvalidThreshold is returned when a threshold is not between one
+	// and the number of shares.
+	ErrInvalidThreshold = bls.ErrInvalidThreshold
+
+	// ErrNotEnoughShares is returned when fewer shares than the threshold
+	// are given.
+	ErrNotEnoughShares = bls.ErrNotEnoughShares
+
+	// ErrInvalidShareIndex is returned when a share index is zero or does
+	// not match the share it is checked against.
+	ErrInvalidShareIndex = bls.ErrInvalidShareIndex
+
+	// ErrDuplicateShareIndex is returned when two shares have the same
+	// index.
+	ErrDuplicateShareIndex = bls.ErrDuplicateShareIndex
+
+	// ErrSignatureCountMismatch is returned when the number of signatures
+	// and public keys differ.
+	ErrSignatureCountMismatch = bls.ErrSignatureCountMismatch
+)
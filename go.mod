@@ -9,6 +9,7 @@ require (
 	golang.org/x/crypto v0.0.0-20190325154230-a5d413f7728c
 	golang.org/x/net v0.0.0-20190326090315-15845e8f865b // indirect
 	golang.org/x/sys v0.0.0-20190322080309-f49334f85ddc // indirect
+	golang.org/x/text v0.3.6
 	golang.org/x/tools v0.0.0-20190325223049-1d95b17f1b04 // indirect
 )
 
@@ -0,0 +1,169 @@
+package bls
+
+// GTSize is the size of a serialized GT element in bytes.
+const GTSize = 576
+
+// GT is an element of the order-r subgroup of FQ12 that
+// pairings map into.
+type GT struct {
+	f *FQ12
+}
+
+// GTOne is the identity element of GT.
+var GTOne = NewGT(FQ12One)
+
+// NewGT creates a GT element from an FQ12 element without
+// checking that it is in the correct subgroup.
+func NewGT(f *FQ12) *GT {
+	return &GT{f: f.Copy()}
+}
+
+// PairingGT performs a pairing given the G1 and G2 elements and
+// returns the result as a GT element.
+func PairingGT(p *G1Projective, q *G2Projective) *GT {
+	return &GT{f: Pairing(p, q)}
+}
+
+// FQ12 gets a copy of the underlying FQ12 element.
+func (g *GT) FQ12() *FQ12 {
+	return g.f.Copy()
+}
+
+// Copy returns a copy of the GT element.
+func (g *GT) Copy() *GT {
+	return &GT{f: g.f.Copy()}
+}
+
+func (g *GT) String() string {
+	return g.f.String()
+}
+
+// Equal checks if two GT elements are equal.
+func (g *GT) Equal(other *GT) bool {
+	return g.f.Equals(other.f)
+}
+
+// IsOne checks if the GT element is the identity.
+func (g *GT) IsOne() bool {
+	return g.f.Equals(FQ12One)
+}
+
+// MulAssign multiplies the GT element by another.
+func (g *GT) MulAssign(other *GT) {
+	g.f.MulAssign(other.f)
+}
+
+// Mul multiplies two GT elements and returns the result.
+func (g *GT) Mul(other *GT) *GT {
+	out := g.Copy()
+	out.MulAssign(other)
+	return out
+}
+
+// InverseAssign inverts the GT element. Since GT lies in the
+// cyclotomic subgroup, the inverse is the conjugate.
+func (g *GT) InverseAssign() {
+	g.f.ConjugateAssign()
+}
+
+// Inverse returns the inverse of the GT element.
+func (g *GT) Inverse() *GT {
+	out := g.Copy()
+	out.InverseAssign()
+	return out
+}
+
+// cyclotomicExp raises an element of the cyclotomic subgroup to
+// the power n.
+func cyclotomicExp(f *FQ12, n []uint64) *FQ12 {
+	res := FQ12One.Copy()
+	foundOne := false
+	iter := NewBitIterator(n)
+	next, done := iter.Next()
+	for !done {
+		if foundOne {
+			res.CyclotomicSquareAssign()
+		} else {
+			foundOne = next
+		}
+		if next {
+			res.MulAssign(f)
+		}
+		next, done = iter.Next()
+	}
+	return res
+}
+
+// Exp raises the GT element to the power of a scalar.
+func (g *GT) Exp(f *FR) *GT {
+	n := f.ToRepr()
+	return &GT{f: cyclotomicExp(g.f, n[:])}
+}
+
+// isCyclotomic checks if f^(q^4 - q^2 + 1) = 1.
+func isCyclotomic(f *FQ12) bool {
+	if f.IsZero() {
+		return false
+	}
+	lhs := f.Copy()
+	lhs.FrobeniusMapAssign(4)
+	lhs.MulAssign(f)
+	rhs := f.Copy()
+	rhs.FrobeniusMapAssign(2)
+	return lhs.Equals(rhs)
+}
+
+// IsInCorrectSubgroup checks if the element is in the order-r
+// subgroup of FQ12.
+func (g *GT) IsInCorrectSubgroup() bool {
+	if !isCyclotomic(g.f) {
+		return false
+	}
+	return cyclotomicExp(g.f, RFieldModulus[:]).Equals(FQ12One)
+}
+
+func putFQ6(out []byte, f *FQ6) {
+	for i, c := range []FQ2{f.c0, f.c1, f.c2} {
+		putFQ(out[96*i:], c.c0)
+		putFQ(out[96*i+48:], c.c1)
+	}
+}
+
+func getFQ6(in []byte) (*FQ6, error) {
+	var c [6]FQ
+	for i := range c {
+		f, err := getFQ(in[48*i : 48*(i+1)])
+		if err != nil {
+			return nil, err
+		}
+		c[i] = f
+	}
+	return NewFQ6(NewFQ2(c[0], c[1]), NewFQ2(c[2], c[3]), NewFQ2(c[4], c[5])), nil
+}
+
+// Serialize serializes the GT element as twelve 48-byte big-endian
+// FQ elements in the order c0.c0.c0, c0.c0.c1, c0.c1.c0, ... c1.c2.c1.
+func (g *GT) Serialize() [GTSize]byte {
+	var out [GTSize]byte
+	putFQ6(out[:288], g.f.c0)
+	putFQ6(out[288:], g.f.c1)
+	return out
+}
+
+// DeserializeGT deserializes a GT element and checks that it is
+// in the correct subgroup.
+func DeserializeGT(b [GTSize]byte) (*GT, error) {
+	c0, err := getFQ6(b[:288])
+	if err != nil {
+		return nil, err
+	}
+	c1, err := getFQ6(b[288:])
+	if err != nil {
+		return nil, err
+	}
+	g := &GT{f: NewFQ12(c0, c1)}
+	if !g.IsInCorrectSubgroup() {
+		return nil, ErrNotInSubgroup
+	}
+	return g, nil
+}
@@ -0,0 +1,148 @@
+package bls_test
+
+import (
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+func TestGTExpBilinearity(t *testing.T) {
+	r := NewXORShift(1)
+	for i := 0; i < 3; i++ {
+		p, _ := bls.RandG1(r)
+		q, _ := bls.RandG2(r)
+		a, _ := bls.RandFR(r)
+
+		e := bls.PairingGT(p, q)
+		lhs := bls.PairingGT(p.ToAffine().MulFR(a.ToRepr()), q)
+		if !lhs.Equal(e.Exp(a)) {
+			t.Fatal("e(aP, Q) should equal e(P, Q)^a")
+		}
+
+		rhs := bls.PairingGT(p, q.ToAffine().MulFR(a.ToRepr()))
+		if !rhs.Equal(lhs) {
+			t.Fatal("e(aP, Q) should equal e(P, aQ)")
+		}
+	}
+}
+
+func TestGTMulAndInverse(t *testing.T) {
+	r := NewXORShift(2)
+	p, _ := bls.RandG1(r)
+	q, _ := bls.RandG2(r)
+	e := bls.PairingGT(p, q)
+
+	if !e.Mul(e.Inverse()).IsOne() {
+		t.Fatal("element multiplied by its inverse should be one")
+	}
+
+	e2 := bls.PairingGT(p.Double(), q)
+	if !e.Mul(e).Equal(e2) {
+		t.Fatal("e(P, Q)^2 should equal e(2P, Q)")
+	}
+
+	minusOne := bls.FRReprToFR(bls.NewFRRepr(0))
+	minusOne.SubAssign(bls.FRReprToFR(bls.NewFRRepr(1)))
+	if !e.Exp(minusOne).Equal(e.Inverse()) {
+		t.Fatal("e^(r-1) should equal the inverse of e")
+	}
+
+	zero := bls.FRReprToFR(bls.NewFRRepr(0))
+	if !e.Exp(zero).IsOne() {
+		t.Fatal("e^0 should be one")
+	}
+}
+
+func TestCyclotomicSquare(t *testing.T) {
+	r := NewXORShift(3)
+	p, _ := bls.RandG1(r)
+	q, _ := bls.RandG2(r)
+	f := bls.Pairing(p, q)
+
+	expected := f.Copy()
+	expected.SquareAssign()
+	f.CyclotomicSquareAssign()
+	if !f.Equals(expected) {
+		t.Fatal("cyclotomic squaring does not match squaring")
+	}
+}
+
+func TestGTSubgroupCheck(t *testing.T) {
+	r := NewXORShift(4)
+	p, _ := bls.RandG1(r)
+	q, _ := bls.RandG2(r)
+	if !bls.PairingGT(p, q).IsInCorrectSubgroup() {
+		t.Fatal("pairing output should be in GT")
+	}
+	if !bls.GTOne.IsInCorrectSubgroup() {
+		t.Fatal("one should be in GT")
+	}
+
+	f, _ := bls.RandFQ12(r)
+	if bls.NewGT(f).IsInCorrectSubgroup() {
+		t.Fatal("random FQ12 element should not be in GT")
+	}
+
+	// map into the cyclotomic subgroup with the easy part of the
+	// final exponentiation; the result is almost certainly not of order r
+	inv := f.Copy()
+	inv.InverseAssign()
+	f.ConjugateAssign()
+	f.MulAssign(inv)
+	f2 := f.Copy()
+	f.FrobeniusMapAssign(2)
+	f.MulAssign(f2)
+	if bls.NewGT(f).IsInCorrectSubgroup() {
+		t.Fatal("cyclotomic element of the wrong order should not be in GT")
+	}
+
+	if bls.NewGT(bls.FQ12Zero).IsInCorrectSubgroup() {
+		t.Fatal("zero should not be in GT")
+	}
+}
+
+func TestGTSerializeRoundTrip(t *testing.T) {
+	r := NewXORShift(5)
+	for i := 0; i < 3; i++ {
+		p, _ := bls.RandG1(r)
+		q, _ := bls.RandG2(r)
+		e := bls.PairingGT(p, q)
+
+		b := e.Serialize()
+		e2, err := bls.DeserializeGT(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e.Equal(e2) {
+			t.Fatal("GT element did not round trip")
+		}
+		if e2.Serialize() != b {
+			t.Fatal("GT serialization is not stable")
+		}
+	}
+
+	one := bls.GTOne.Serialize()
+	if one[47] != 1 {
+		t.Fatal("serialized one should have the first coefficient set to one")
+	}
+	for i, v := range one {
+		if i != 47 && v != 0 {
+			t.Fatal("serialized one should have all other bytes zero")
+		}
+	}
+}
+
+func TestGTDeserializeInvalid(t *testing.T) {
+	r := NewXORShift(6)
+	f, _ := bls.RandFQ12(r)
+	if _, err := bls.DeserializeGT(bls.NewGT(f).Serialize()); err == nil {
+		t.Fatal("expected error deserializing element outside GT")
+	}
+
+	b := bls.GTOne.Serialize()
+	modulus := bls.QFieldModulus.Bytes()
+	copy(b[48:96], modulus[:])
+	if _, err := bls.DeserializeGT(b); err == nil {
+		t.Fatal("expected error deserializing non-canonical coefficient")
+	}
+}
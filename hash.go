@@ -6,7 +6,8 @@ import (
 	"math/big"
 )
 
-func HashSecretKey(b [32]byte) *FR {
+// HashSecretKey hashes 32 bytes of key material to a scalar.
+func HashSecretKey(b [32]byte) Scalar {
 	// this implements hash_to_field as defined in the IETF standard with:
 	// msg = b
 	// ctr = 0
@@ -35,7 +36,7 @@ func HashSecretKey(b [32]byte) *FR {
 	tBig.SetBytes(t)
 	tBig.Mod(tBig, RFieldModulus.ToBig())
 	tFR, _ := FRReprFromBigInt(tBig)
-	return FRReprToFR(tFR)
+	return ScalarReprToScalar(*tFR)
 }
 
 func hp(msg []byte, ctr uint8) FQ {
@@ -330,6 +331,213 @@ func HashG1(msg []byte) *G1Affine {
 	return optimizedSWUMap(&t1, &t2)
 }
 
+// MaxDSTLength is the maximum length of a domain separation tag that
+// expand_message_xmd uses as is. Longer tags are first hashed.
+const MaxDSTLength = 255
+
+// oversizeDSTPrefix is the prefix used to hash tags longer than
+// MaxDSTLength, from section 5.3.3 of RFC 9380.
+const oversizeDSTPrefix = "H2C-OVERSIZE-DST-"
+
+// expandMessageXMD implements expand_message_xmd from RFC 9380 with
+// SHA-256. The tag is framed as DST || I2OSP(len(DST), 1) after the
+// message, so hashes with a tag never collide with HashG1 or HashG2.
+func expandMessageXMD(msg []byte, dst []byte, lenInBytes int) []byte {
+	if len(dst) > MaxDSTLength {
+		h := sha256.Sum256(append([]byte(oversizeDSTPrefix), dst...))
+		dst = h[:]
+	}
+	ell := (lenInBytes + sha256.Size - 1) / sha256.Size
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	h := sha256.New()
+	h.Write(make([]byte, sha256.BlockSize))
+	h.Write(msg)
+	h.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	h.Reset()
+	h.Write(b0)
+	h.Write([]byte{1})
+	h.Write(dstPrime)
+	bi := h.Sum(nil)
+
+	out := make([]byte, 0, ell*sha256.Size)
+	out = append(out, bi...)
+	for i := 2; i <= ell; i++ {
+		x := make([]byte, sha256.Size)
+		for j := range x {
+			x[j] = b0[j] ^ bi[j]
+		}
+		h.Reset()
+		h.Write(x)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		bi = h.Sum(nil)
+		out = append(out, bi...)
+	}
+	return out[:lenInBytes]
+}
+
+// hashToFieldL is the number of bytes hashed to each FQ element.
+const hashToFieldL = 64
+
+func fqFromUniformBytes(b []byte) FQ {
+	tBig := new(big.Int).SetBytes(b)
+	tBig.Mod(tBig, QFieldModulus.ToBig())
+	tFQ, _ := FQReprFromBigInt(tBig)
+	return FQReprToFQ(tFQ)
+}
+
+// hashToFieldFQ implements hash_to_field from RFC 9380 for two FQ
+// elements.
+func hashToFieldFQ(msg []byte, dst []byte) (FQ, FQ) {
+	b := expandMessageXMD(msg, dst, 2*hashToFieldL)
+	return fqFromUniformBytes(b[:hashToFieldL]), fqFromUniformBytes(b[hashToFieldL:])
+}
+
+// hashToFieldFQ2 implements hash_to_field from RFC 9380 for two FQ2
+// elements.
+func hashToFieldFQ2(msg []byte, dst []byte) (FQ2, FQ2) {
+	b := expandMessageXMD(msg, dst, 4*hashToFieldL)
+	u0 := NewFQ2(fqFromUniformBytes(b[:hashToFieldL]), fqFromUniformBytes(b[hashToFieldL:2*hashToFieldL]))
+	u1 := NewFQ2(fqFromUniformBytes(b[2*hashToFieldL:3*hashToFieldL]), fqFromUniformBytes(b[3*hashToFieldL:]))
+	return u0, u1
+}
+
+// sgn0FQ is the sgn0 function from RFC 9380.
+func sgn0FQ(f FQ) bool {
+	return f.ToRepr().IsOdd()
+}
+
+// sgn0FQ2 is the sgn0 function from RFC 9380.
+func sgn0FQ2(f FQ2) bool {
+	return sgn0FQ(f.c0) || (f.c0.IsZero() && sgn0FQ(f.c1))
+}
+
+// sswuZ is the Z constant of the simplified SWU map for Ell1 in RFC 9380.
+var sswuZ = FQReprToFQ(NewFQRepr(11))
+
+// sswuMap implements map_to_curve_simple_swu from RFC 9380 for the curve
+// isogenous to G1.
+func sswuMap(u FQ) *G1Affine {
+	zu2 := u.Copy()
+	zu2.SquareAssign()
+	zu2.MulAssign(sswuZ)
+
+	tv1 := zu2.Copy()
+	tv1.SquareAssign()
+	tv1.AddAssign(zu2)
+
+	var x1 FQ
+	if tv1.IsZero() {
+		x1 = sswuZ.Copy()
+		x1.MulAssign(ellPA)
+		x1, _ = x1.Inverse()
+		x1.MulAssign(ellPB)
+	} else {
+		tv1, _ = tv1.Inverse()
+		tv1.AddAssign(FQOne)
+		x1 = ellPB.Copy()
+		x1.NegAssign()
+		x1.DivAssign(ellPA)
+		x1.MulAssign(tv1)
+	}
+
+	g := func(x FQ) FQ {
+		gx := x.Copy()
+		gx.SquareAssign()
+		gx.AddAssign(ellPA)
+		gx.MulAssign(x)
+		gx.AddAssign(ellPB)
+		return gx
+	}
+
+	x := x1
+	y, found := g(x1).Sqrt()
+	if !found {
+		x = zu2.Copy()
+		x.MulAssign(x1)
+		y, found = g(x).Sqrt()
+		if !found {
+			panic("this should never happen")
+		}
+	}
+	if sgn0FQ(u) != sgn0FQ(y) {
+		y.NegAssign()
+	}
+	return NewG1Affine(x, y)
+}
+
+// sswuZ2 is the Z constant of the simplified SWU map for Ell2 in RFC 9380.
+var sswuZ2 = func() FQ2 {
+	z := NewFQ2(FQReprToFQ(NewFQRepr(2)), FQOne)
+	z.NegAssign()
+	return z
+}()
+
+// sswuMap2 implements map_to_curve_simple_swu from RFC 9380 for the curve
+// isogenous to G2.
+func sswuMap2(u FQ2) *G2Affine {
+	zu2 := u.Copy()
+	zu2.SquareAssign()
+	zu2.MulAssign(sswuZ2)
+
+	tv1 := zu2.Copy()
+	tv1.SquareAssign()
+	tv1.AddAssign(zu2)
+
+	var x1 FQ2
+	if tv1.IsZero() {
+		x1 = sswuZ2.Copy()
+		x1.MulAssign(ell2pA)
+		x1.InverseAssign()
+		x1.MulAssign(ell2pB)
+	} else {
+		tv1.InverseAssign()
+		tv1.AddAssign(FQ2One)
+		x1 = ell2pB.Copy()
+		x1.NegAssign()
+		x1.DivAssign(ell2pA)
+		x1.MulAssign(tv1)
+	}
+
+	g := func(x FQ2) FQ2 {
+		gx := x.Copy()
+		gx.SquareAssign()
+		gx.AddAssign(ell2pA)
+		gx.MulAssign(x)
+		gx.AddAssign(ell2pB)
+		return gx
+	}
+
+	x := x1
+	y, found := g(x1).Sqrt()
+	if !found {
+		x = zu2.Copy()
+		x.MulAssign(x1)
+		y, found = g(x).Sqrt()
+		if !found {
+			panic("this should never happen")
+		}
+	}
+	if sgn0FQ2(u) != sgn0FQ2(y) {
+		y.NegAssign()
+	}
+	return NewG2Affine(x, y)
+}
+
+// HashG1WithDST converts a message to a point on the G1 curve using
+// hash_to_curve from RFC 9380 with expand_message_xmd and the given
+// domain separation tag. Tags longer than MaxDSTLength bytes are
+// hashed as described in section 5.3.3 of RFC 9380.
+func HashG1WithDST(msg []byte, dst []byte) *G1Affine {
+	u0, u1 := hashToFieldFQ(msg, dst)
+	q := iso11(sswuMap(u0)).ToProjective().AddAffine(iso11(sswuMap(u1)))
+	return ClearH(q.ToAffine())
+}
+
 var iwsc = NewFQ2(
 	FQReprToFQ(fqReprFromHexUnchecked("d0088f51cbff34d258dd3db21a5d66bb23ba5c279c2895fb39869507b587b120f55ffff58a9ffffdcff7fffffffd556")),
 	FQReprToFQ(fqReprFromHexUnchecked("d0088f51cbff34d258dd3db21a5d66bb23ba5c279c2895fb39869507b587b120f55ffff58a9ffffdcff7fffffffd555")),
@@ -409,3 +617,13 @@ func HashG2(msg []byte) *G2Affine {
 	h := optimizedSWUMap2(&t1, &t2)
 	return h
 }
+
+// HashG2WithDST converts a message to a point on the G2 curve using
+// hash_to_curve from RFC 9380 with expand_message_xmd and the given
+// domain separation tag. Tags longer than MaxDSTLength bytes are
+// hashed as described in section 5.3.3 of RFC 9380.
+func HashG2WithDST(msg []byte, dst []byte) *G2Affine {
+	u0, u1 := hashToFieldFQ2(msg, dst)
+	q := iso3(sswuMap2(u0)).ToProjective().AddAffine(iso3(sswuMap2(u1)))
+	return clearH2(q.ToAffine())
+}
@@ -81,6 +81,96 @@ func TestHashG2WithDomain(t *testing.T) {
 	}
 }
 
+func TestHashWithDST(t *testing.T) {
+	msg := []byte("the message to be signed")
+
+	g1a := bls.HashG1WithDST(msg, []byte("DST-A"))
+	g1b := bls.HashG1WithDST(msg, []byte("DST-B"))
+	if g1a.Equals(g1b) || g1a.Equals(bls.HashG1(msg)) {
+		t.Fatal("different tags should hash to different G1 points")
+	}
+	if !g1a.Equals(bls.HashG1WithDST(msg, []byte("DST-A"))) {
+		t.Fatal("hashing to G1 with a tag should be deterministic")
+	}
+	if !g1a.IsInCorrectSubgroupAssumingOnCurve() {
+		t.Fatal("hash to G1 with a tag should be in the subgroup")
+	}
+
+	g2a := bls.HashG2WithDST(msg, []byte("DST-A"))
+	g2b := bls.HashG2WithDST(msg, []byte("DST-B"))
+	if g2a.ToProjective().Equals(g2b.ToProjective()) || g2a.ToProjective().Equals(bls.HashG2(msg).ToProjective()) {
+		t.Fatal("different tags should hash to different G2 points")
+	}
+	if !g2a.IsInCorrectSubgroupAssumingOnCurve() {
+		t.Fatal("hash to G2 with a tag should be in the subgroup")
+	}
+}
+
+// Test vectors from RFC 9380, appendices J.9.1 and J.10.1.
+var hashWithDSTTests = []struct {
+	msg string
+	g1  string
+	g2  string
+}{
+	{
+		msg: "",
+		g1:  "052926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a108ba738453bfed09cb546dbb0783dbb3a5f1f566ed67bb6be0e8c67e2e81a4cc68ee29813bb7994998f3eae0c9c6a265",
+		g2:  "05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d60503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92",
+	},
+	{
+		msg: "abc",
+		g1:  "03567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f69030b9c15f3fe6e5cf4211f346271d7b01c8f3b28be689c8429c85b67af215533311f0b8dfaaa154fa6b88176c229f2885d",
+		g2:  "139cddbccdc5e91b9623efd38c49f81a6f83f175e80b06fc374de9eb4b41dfe4ca3a230ed250fbe3a2acf73a41177fd802c2d18e033b960562aae3cab37a27ce00d80ccd5ba4b7fe0e7a210245129dbec7780ccc7954725f4168aff2787776e600aa65dae3c8d732d10ecd2c50f8a1baf3001578f71c694e03866e9f3d49ac1e1ce70dd94a733534f106d4cec0eddd161787327b68159716a37440985269cf584bcb1e621d3a7202be6ea05c4cfe244aeb197642555a0645fb87bf7466b2ba48",
+	},
+}
+
+func TestHashWithDSTVectors(t *testing.T) {
+	dstG1 := []byte("QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_")
+	dstG2 := []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	for _, test := range hashWithDSTTests {
+		g1 := bls.SerializeG1Uncompressed(bls.HashG1WithDST([]byte(test.msg), dstG1))
+		if hex.EncodeToString(g1[:]) != test.g1 {
+			t.Fatalf("hash to G1 of %q does not match RFC 9380: %x", test.msg, g1)
+		}
+		g2 := bls.SerializeG2Uncompressed(bls.HashG2WithDST([]byte(test.msg), dstG2))
+		if hex.EncodeToString(g2[:]) != test.g2 {
+			t.Fatalf("hash to G2 of %q does not match RFC 9380: %x", test.msg, g2)
+		}
+	}
+}
+
+func TestHashWithDSTNoCollision(t *testing.T) {
+	// A tag must not act as a prefix of a message hashed without one.
+	msg := []byte("the message to be signed")
+	dst := []byte("D")
+	prefixed := append(append([]byte{}, dst...), msg...)
+
+	if bls.HashG1WithDST(msg, dst).Equals(bls.HashG1(prefixed)) {
+		t.Fatal("hash to G1 with a tag collides with the untagged hash")
+	}
+	if bls.HashG2WithDST(msg, dst).ToProjective().Equals(bls.HashG2(prefixed).ToProjective()) {
+		t.Fatal("hash to G2 with a tag collides with the untagged hash")
+	}
+}
+
+func TestHashWithOversizeDST(t *testing.T) {
+	// Tags longer than MaxDSTLength are replaced by their hash, as in
+	// section 5.3.3 of RFC 9380.
+	msg := []byte("abc")
+	long := bytes.Repeat([]byte{'D'}, bls.MaxDSTLength+1)
+	h := sha256.Sum256(append([]byte("H2C-OVERSIZE-DST-"), long...))
+
+	if !bls.HashG1WithDST(msg, long).Equals(bls.HashG1WithDST(msg, h[:])) {
+		t.Fatal("hash to G1 does not hash an oversize tag")
+	}
+	if !bls.HashG2WithDST(msg, long).ToProjective().Equals(bls.HashG2WithDST(msg, h[:]).ToProjective()) {
+		t.Fatal("hash to G2 does not hash an oversize tag")
+	}
+	if bls.HashG1WithDST(msg, long).Equals(bls.HashG1WithDST(msg, long[:bls.MaxDSTLength])) {
+		t.Fatal("an oversize tag collides with its prefix")
+	}
+}
+
 func BenchmarkHashG2(t *testing.B) {
 	data := make([][]byte, 100)
 	r := NewXORShift(2)
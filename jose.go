@@ -0,0 +1,108 @@
+package bls
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JWK key types. OKP keys carry the compressed public key in x, and EC
+// keys carry the uncompressed coordinates in x and y.
+const (
+	JWKKeyTypeOKP = "OKP"
+	JWKKeyTypeEC  = "EC"
+)
+
+// JWK curve names for keys whose public key is in G1 or G2.
+const (
+	JWKCurveG1 = "BLS12381G1"
+	JWKCurveG2 = "BLS12381G2"
+)
+
+// JWK is a JSON Web Key for a BLS12-381 key as described in
+// draft-ietf-cose-bls-key-representations. All byte members are
+// unpadded base64url and big-endian.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+	D   string `json:"d,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// EncodeBase64URL encodes bytes as unpadded base64url.
+func EncodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeBase64URL decodes unpadded base64url and checks that it decodes
+// to exactly size bytes.
+func DecodeBase64URL(s string, size int) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	if err := checkLength(b, size); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+type jwsHeader struct {
+	Alg  string          `json:"alg"`
+	Typ  string          `json:"typ,omitempty"`
+	Kid  string          `json:"kid,omitempty"`
+	Crit json.RawMessage `json:"crit,omitempty"`
+}
+
+// SignJWSCompact creates a JWS in compact serialization with only the
+// alg header set. sign is called with the JWS signing input.
+func SignJWSCompact(alg string, payload []byte, sign func(signingInput []byte) ([]byte, error)) (string, error) {
+	header, err := json.Marshal(jwsHeader{Alg: alg})
+	if err != nil {
+		return "", err
+	}
+	signingInput := EncodeBase64URL(header) + "." + EncodeBase64URL(payload)
+	sig, err := sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + EncodeBase64URL(sig), nil
+}
+
+// ParseJWSCompact splits a JWS in compact serialization and checks that
+// its header names alg and has no critical extensions. It returns the
+// signing input, the payload and the signature, which the caller must
+// verify before trusting the payload.
+func ParseJWSCompact(token string, alg string) (signingInput []byte, payload []byte, sig []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, fmt.Errorf("%w: JWS must have three parts", ErrInvalidEncoding)
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	var header jwsHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	if header.Alg != alg {
+		return nil, nil, nil, fmt.Errorf("%w: JWS algorithm %q", ErrUnexpectedAlgorithm, header.Alg)
+	}
+	if len(header.Crit) != 0 {
+		return nil, nil, nil, fmt.Errorf("%w: unsupported critical JWS header", ErrInvalidEncoding)
+	}
+	payload, err = base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	sig, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+	}
+	return []byte(parts[0] + "." + parts[1]), payload, sig, nil
+}
@@ -0,0 +1,64 @@
+package bls_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+func TestDecodeBase64URL(t *testing.T) {
+	b, err := bls.DecodeBase64URL(bls.EncodeBase64URL([]byte{0xfb, 0xff, 0x01}), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b[0] != 0xfb || b[1] != 0xff || b[2] != 0x01 {
+		t.Fatal("bytes did not round trip through base64url")
+	}
+	if _, err := bls.DecodeBase64URL("-_8B", 4); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatal("expected error decoding the wrong length")
+	}
+	if _, err := bls.DecodeBase64URL("+/8B", 3); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatal("expected error decoding standard base64")
+	}
+	if _, err := bls.DecodeBase64URL("-_8B==", 3); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatal("expected error decoding padded base64url")
+	}
+}
+
+func TestJWSCompact(t *testing.T) {
+	token, err := bls.SignJWSCompact("TEST", []byte("payload"), func(signingInput []byte) ([]byte, error) {
+		return []byte("sig"), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, "eyJhbGciOiJURVNUIn0.") {
+		t.Fatalf("unexpected JWS header in %s", token)
+	}
+
+	signingInput, payload, sig, err := bls.ParseJWSCompact(token, "TEST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != "payload" || string(sig) != "sig" {
+		t.Fatal("payload or signature did not round trip")
+	}
+	if !strings.HasPrefix(token, string(signingInput)+".") {
+		t.Fatal("signing input should be the first two parts of the token")
+	}
+
+	if _, _, _, err := bls.ParseJWSCompact(token, "OTHER"); !errors.Is(err, bls.ErrUnexpectedAlgorithm) {
+		t.Fatalf("expected ErrUnexpectedAlgorithm, got %v", err)
+	}
+	if _, _, _, err := bls.ParseJWSCompact(token+".", "TEST"); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding, got %v", err)
+	}
+
+	crit := bls.EncodeBase64URL([]byte(`{"alg":"TEST","crit":["exp"]}`))
+	parts := strings.SplitN(token, ".", 2)
+	if _, _, _, err := bls.ParseJWSCompact(crit+"."+parts[1], "TEST"); !errors.Is(err, bls.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for a critical header, got %v", err)
+	}
+}
@@ -0,0 +1,389 @@
+// Package keystore implements EIP-2335 encrypted keystores for BLS
+// secret keys.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/phoreproject/bls/g1pubs"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Version is the keystore version implemented by this package.
+const Version = 4
+
+// KDF functions supported by keystores.
+const (
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+)
+
+// DefaultCost is the scrypt N and the PBKDF2 iteration count used when
+// encrypting, as recommended by EIP-2335.
+const DefaultCost = 262144
+
+// Limits on the KDF parameters of a keystore, so that decrypting an
+// untrusted keystore cannot use unbounded time or memory. The scrypt
+// memory is 128 * N * r bytes.
+const (
+	MaxScryptN          = 1 << 20
+	MaxScryptR          = 32
+	MaxScryptP          = 16
+	MaxScryptMemory     = 1 << 30
+	MaxPBKDF2Iterations = 1 << 24
+)
+
+const (
+	checksumFunction = "sha256"
+	cipherFunction   = "aes-128-ctr"
+	pbkdf2PRF        = "hmac-sha256"
+	derivedKeyLength = 32
+	saltLength       = 32
+	scryptR          = 8
+	scryptP          = 1
+)
+
+var (
+	// ErrInvalidPassword is returned when the checksum of a keystore does
+	// not match the password.
+	ErrInvalidPassword = errors.New("invalid keystore password")
+
+	// ErrInvalidKeystore is returned when a keystore is malformed or uses
+	// an unsupported function.
+	ErrInvalidKeystore = errors.New("invalid keystore")
+)
+
+// Module is a step of the keystore crypto: a function, its parameters and
+// its message.
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+// Crypto holds the key derivation, checksum and cipher of a keystore.
+type Crypto struct {
+	KDF      Module `json:"kdf"`
+	Checksum Module `json:"checksum"`
+	Cipher   Module `json:"cipher"`
+}
+
+// Keystore is an EIP-2335 keystore.
+type Keystore struct {
+	Crypto      Crypto `json:"crypto"`
+	Description string `json:"description"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+type scryptParams struct {
+	DKLen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	DKLen int    `json:"dklen"`
+	C     int    `json:"c"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// Options are the options for Encrypt.
+type Options struct {
+	// KDF is KDFScrypt or KDFPBKDF2. It defaults to KDFScrypt.
+	KDF string
+
+	// Cost is the scrypt N or the PBKDF2 iteration count. It defaults to
+	// DefaultCost and is at most MaxScryptN or MaxPBKDF2Iterations.
+	Cost int
+
+	// Path is the EIP-2334 path the key was derived at, if any.
+	Path string
+
+	// Description is a description of the key.
+	Description string
+}
+
+// processPassword normalizes a password to NFKD and removes control
+// codes.
+func processPassword(password string) []byte {
+	normalized := norm.NFKD.String(password)
+	return []byte(strings.Map(func(r rune) rune {
+		if r < 0x20 || (r >= 0x7f && r <= 0x9f) {
+			return -1
+		}
+		return r
+	}, normalized))
+}
+
+func invalidf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidKeystore, fmt.Sprintf(format, args...))
+}
+
+func decodeHexField(name string, s string) ([]byte, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, invalidf("%s is not hex", name)
+	}
+	return b, nil
+}
+
+// deriveKey runs the KDF of the keystore.
+func (k *Keystore) deriveKey(password []byte) ([]byte, error) {
+	switch k.Crypto.KDF.Function {
+	case KDFScrypt:
+		var p scryptParams
+		if err := json.Unmarshal(k.Crypto.KDF.Params, &p); err != nil {
+			return nil, invalidf("scrypt params: %v", err)
+		}
+		if p.DKLen != derivedKeyLength {
+			return nil, invalidf("unsupported dklen %d", p.DKLen)
+		}
+		if p.N <= 1 || p.N > MaxScryptN || p.N&(p.N-1) != 0 {
+			return nil, invalidf("invalid scrypt n %d", p.N)
+		}
+		if p.R <= 0 || p.R > MaxScryptR || 128*p.N*p.R > MaxScryptMemory {
+			return nil, invalidf("invalid scrypt r %d", p.R)
+		}
+		if p.P <= 0 || p.P > MaxScryptP {
+			return nil, invalidf("invalid scrypt p %d", p.P)
+		}
+		salt, err := decodeHexField("salt", p.Salt)
+		if err != nil {
+			return nil, err
+		}
+		key, err := scrypt.Key(password, salt, p.N, p.R, p.P, p.DKLen)
+		if err != nil {
+			return nil, invalidf("scrypt params: %v", err)
+		}
+		return key, nil
+	case KDFPBKDF2:
+		var p pbkdf2Params
+		if err := json.Unmarshal(k.Crypto.KDF.Params, &p); err != nil {
+			return nil, invalidf("pbkdf2 params: %v", err)
+		}
+		if p.DKLen != derivedKeyLength {
+			return nil, invalidf("unsupported dklen %d", p.DKLen)
+		}
+		if p.PRF != pbkdf2PRF {
+			return nil, invalidf("unsupported prf %q", p.PRF)
+		}
+		if p.C <= 0 || p.C > MaxPBKDF2Iterations {
+			return nil, invalidf("invalid iteration count %d", p.C)
+		}
+		salt, err := decodeHexField("salt", p.Salt)
+		if err != nil {
+			return nil, err
+		}
+		return pbkdf2.Key(password, salt, p.C, p.DKLen, sha256.New), nil
+	}
+	return nil, invalidf("unsupported kdf %q", k.Crypto.KDF.Function)
+}
+
+func checksum(key []byte, cipherMessage []byte) []byte {
+	h := sha256.New()
+	h.Write(key[16:32])
+	h.Write(cipherMessage)
+	return h.Sum(nil)
+}
+
+func aes128CTR(key []byte, iv []byte, in []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key[:16])
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(in))
+	cipher.NewCTR(block, iv).XORKeyStream(out, in)
+	return out, nil
+}
+
+// Decrypt decrypts the secret key with a password. If the keystore has a
+// public key, it must match the secret key.
+func (k *Keystore) Decrypt(password string) (*g1pubs.SecretKey, error) {
+	if k.Version != Version {
+		return nil, invalidf("unsupported version %d", k.Version)
+	}
+	if k.Crypto.Checksum.Function != checksumFunction {
+		return nil, invalidf("unsupported checksum %q", k.Crypto.Checksum.Function)
+	}
+	if k.Crypto.Cipher.Function != cipherFunction {
+		return nil, invalidf("unsupported cipher %q", k.Crypto.Cipher.Function)
+	}
+	var cp cipherParams
+	if err := json.Unmarshal(k.Crypto.Cipher.Params, &cp); err != nil {
+		return nil, invalidf("cipher params: %v", err)
+	}
+	iv, err := decodeHexField("iv", cp.IV)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, invalidf("iv must be %d bytes", aes.BlockSize)
+	}
+	cipherMessage, err := decodeHexField("cipher message", k.Crypto.Cipher.Message)
+	if err != nil {
+		return nil, err
+	}
+	expectedChecksum, err := decodeHexField("checksum message", k.Crypto.Checksum.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	p := processPassword(password)
+	defer zeroBytes(p)
+	key, err := k.deriveKey(p)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+	if subtle.ConstantTimeCompare(checksum(key, cipherMessage), expectedChecksum) != 1 {
+		return nil, ErrInvalidPassword
+	}
+
+	secret, err := aes128CTR(key, iv, cipherMessage)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(secret)
+	sk := new(g1pubs.SecretKey)
+	if err := sk.UnmarshalBinary(secret); err != nil {
+		return nil, invalidf("secret key: %v", err)
+	}
+
+	if k.Pubkey != "" {
+		pub, err := k.PublicKey()
+		if err != nil {
+			sk.Zeroize()
+			return nil, err
+		}
+		if !g1pubs.PrivToPub(sk).Equal(pub) {
+			sk.Zeroize()
+			return nil, invalidf("public key does not match secret key")
+		}
+	}
+	return sk, nil
+}
+
+// PublicKey decodes the public key of the keystore.
+func (k *Keystore) PublicKey() (*g1pubs.PublicKey, error) {
+	pub := new(g1pubs.PublicKey)
+	if err := pub.UnmarshalText([]byte(k.Pubkey)); err != nil {
+		return nil, invalidf("pubkey: %v", err)
+	}
+	return pub, nil
+}
+
+func newUUID(r io.Reader) (string, error) {
+	var u [16]byte
+	if _, err := io.ReadFull(r, u[:]); err != nil {
+		return "", err
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
+
+// Encrypt encrypts a secret key with a password, reading the salt, IV and
+// UUID from r. opts may be nil.
+func Encrypt(sk *g1pubs.SecretKey, password string, opts *Options, r io.Reader) (*Keystore, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	kdf := opts.KDF
+	if kdf == "" {
+		kdf = KDFScrypt
+	}
+	cost := opts.Cost
+	if cost == 0 {
+		cost = DefaultCost
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(r, iv); err != nil {
+		return nil, err
+	}
+	id, err := newUUID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var kdfParams interface{}
+	switch kdf {
+	case KDFScrypt:
+		kdfParams = scryptParams{DKLen: derivedKeyLength, N: cost, P: scryptP, R: scryptR, Salt: hex.EncodeToString(salt)}
+	case KDFPBKDF2:
+		kdfParams = pbkdf2Params{DKLen: derivedKeyLength, C: cost, PRF: pbkdf2PRF, Salt: hex.EncodeToString(salt)}
+	default:
+		return nil, invalidf("unsupported kdf %q", kdf)
+	}
+	kdfJSON, err := json.Marshal(kdfParams)
+	if err != nil {
+		return nil, err
+	}
+	cipherJSON, err := json.Marshal(cipherParams{IV: hex.EncodeToString(iv)})
+	if err != nil {
+		return nil, err
+	}
+
+	pub := g1pubs.PrivToPub(sk).Serialize()
+	k := &Keystore{
+		Crypto: Crypto{
+			KDF:      Module{Function: kdf, Params: kdfJSON},
+			Checksum: Module{Function: checksumFunction, Params: json.RawMessage("{}")},
+			Cipher:   Module{Function: cipherFunction, Params: cipherJSON},
+		},
+		Description: opts.Description,
+		Pubkey:      hex.EncodeToString(pub[:]),
+		Path:        opts.Path,
+		UUID:        id,
+		Version:     Version,
+	}
+
+	p := processPassword(password)
+	defer zeroBytes(p)
+	key, err := k.deriveKey(p)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(key)
+
+	secret := sk.Serialize()
+	defer zeroBytes(secret[:])
+	cipherMessage, err := aes128CTR(key, iv, secret[:])
+	if err != nil {
+		return nil, err
+	}
+	k.Crypto.Cipher.Message = hex.EncodeToString(cipherMessage)
+	k.Crypto.Checksum.Message = hex.EncodeToString(checksum(key, cipherMessage))
+	return k, nil
+}
+
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
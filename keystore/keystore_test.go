@@ -0,0 +1,220 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/phoreproject/bls/g1pubs"
+)
+
+// Test vectors from EIP-2335.
+const (
+	vectorPassword = "\U0001d531\U0001d522\U0001d530\U0001d531\U0001d52d\U0001d51e\U0001d530\U0001d530\U0001d534\U0001d52c\U0001d52f\U0001d521\U0001f511"
+	vectorSecret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+)
+
+const scryptVector = `{
+    "crypto": {
+        "kdf": {
+            "function": "scrypt",
+            "params": {
+                "dklen": 32,
+                "n": 262144,
+                "p": 1,
+                "r": 8,
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "d2217fe5f3e9a1e34581ef8a78f7c9928e436d36dacc5e846690a5581e8ea484"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "06ae90d55fe0a6e9c5c3bc5b170827b2e5cce3929ed3f116c2811e6366dfe20f"
+        }
+    },
+    "description": "This is a test keystore that uses scrypt to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/3141592653/589793238",
+    "uuid": "1d85ae20-35c5-4611-98e8-aa14a633906f",
+    "version": 4
+}`
+
+const pbkdf2Vector = `{
+    "crypto": {
+        "kdf": {
+            "function": "pbkdf2",
+            "params": {
+                "dklen": 32,
+                "c": 262144,
+                "prf": "hmac-sha256",
+                "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"
+            },
+            "message": ""
+        },
+        "checksum": {
+            "function": "sha256",
+            "params": {},
+            "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"
+        },
+        "cipher": {
+            "function": "aes-128-ctr",
+            "params": {
+                "iv": "264daa3f303d7259501c93d997d84fe6"
+            },
+            "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"
+        }
+    },
+    "description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+    "pubkey": "9612d7a727c9d0a22e185a1c768478dfe919cada9266988cb32359c11f2b7b27f4ae4040902382ae2910c15e2b420d07",
+    "path": "m/12381/60/0/0",
+    "uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+    "version": 4
+}`
+
+func TestProcessPassword(t *testing.T) {
+	if string(processPassword(vectorPassword)) != "testpassword\U0001f511" {
+		t.Fatalf("unexpected processed password %q", processPassword(vectorPassword))
+	}
+	if string(processPassword("a\x00b\x1fc\x7fd\u0080e\u009ff ")) != "abcdef " {
+		t.Fatal("control codes should be removed")
+	}
+}
+
+func TestDecryptVectors(t *testing.T) {
+	for _, vector := range []string{scryptVector, pbkdf2Vector} {
+		var k Keystore
+		if err := json.Unmarshal([]byte(vector), &k); err != nil {
+			t.Fatal(err)
+		}
+		sk, err := k.Decrypt(vectorPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := sk.Serialize()
+		if hex.EncodeToString(b[:]) != vectorSecret {
+			t.Fatalf("%s keystore decrypted to the wrong secret", k.Crypto.KDF.Function)
+		}
+
+		if _, err := k.Decrypt("wrong password"); !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("expected ErrInvalidPassword, got %v", err)
+		}
+	}
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	sk, _ := g1pubs.RandKey(rand.Reader)
+	for _, kdf := range []string{KDFScrypt, KDFPBKDF2} {
+		k, err := Encrypt(sk, "password", &Options{KDF: kdf, Cost: 1024, Path: "m/12381/3600/0/0/0"}, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Keystore
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Version != Version || decoded.Path != "m/12381/3600/0/0/0" || len(decoded.UUID) != 36 || decoded.UUID[14] != '4' {
+			t.Fatal("unexpected keystore metadata")
+		}
+		pub, err := decoded.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(g1pubs.PrivToPub(sk)) {
+			t.Fatal("keystore public key does not match")
+		}
+
+		sk2, err := decoded.Decrypt("password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sk.Serialize() != sk2.Serialize() {
+			t.Fatalf("secret key did not round trip through a %s keystore", kdf)
+		}
+	}
+
+	if _, err := Encrypt(sk, "password", &Options{KDF: "argon2"}, rand.Reader); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore, got %v", err)
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	sk, _ := g1pubs.RandKey(rand.Reader)
+	other, _ := g1pubs.RandKey(rand.Reader)
+	k, _ := Encrypt(sk, "password", &Options{KDF: KDFPBKDF2, Cost: 16}, rand.Reader)
+
+	mismatched := *k
+	otherPub := g1pubs.PrivToPub(other).Serialize()
+	mismatched.Pubkey = hex.EncodeToString(otherPub[:])
+	if _, err := mismatched.Decrypt("password"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore for a mismatched public key, got %v", err)
+	}
+
+	noPubkey := *k
+	noPubkey.Pubkey = ""
+	if _, err := noPubkey.Decrypt("password"); err != nil {
+		t.Fatal(err)
+	}
+
+	version := *k
+	version.Version = 3
+	if _, err := version.Decrypt("password"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore for version 3, got %v", err)
+	}
+
+	prf := *k
+	prf.Crypto.KDF.Params = json.RawMessage(`{"dklen":32,"c":16,"prf":"hmac-sha512","salt":"00"}`)
+	if _, err := prf.Decrypt("password"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore for an unsupported prf, got %v", err)
+	}
+
+	cipherFn := *k
+	cipherFn.Crypto.Cipher.Function = "aes-256-gcm"
+	if _, err := cipherFn.Decrypt("password"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore for an unsupported cipher, got %v", err)
+	}
+}
+
+func TestDecryptKDFLimits(t *testing.T) {
+	sk, _ := g1pubs.RandKey(rand.Reader)
+	k, _ := Encrypt(sk, "password", &Options{Cost: 16}, rand.Reader)
+
+	for _, params := range []string{
+		`{"dklen":32,"n":2097152,"r":8,"p":1,"salt":"00"}`,
+		`{"dklen":32,"n":1000,"r":8,"p":1,"salt":"00"}`,
+		`{"dklen":32,"n":1048576,"r":16,"p":1,"salt":"00"}`,
+		`{"dklen":32,"n":16,"r":64,"p":1,"salt":"00"}`,
+		`{"dklen":32,"n":16,"r":8,"p":17,"salt":"00"}`,
+		`{"dklen":32,"n":16,"r":0,"p":1,"salt":"00"}`,
+	} {
+		bad := *k
+		bad.Crypto.KDF.Params = json.RawMessage(params)
+		if _, err := bad.Decrypt("password"); !errors.Is(err, ErrInvalidKeystore) {
+			t.Fatalf("expected ErrInvalidKeystore for scrypt params %s, got %v", params, err)
+		}
+	}
+
+	bad := *k
+	bad.Crypto.KDF.Function = KDFPBKDF2
+	bad.Crypto.KDF.Params = json.RawMessage(`{"dklen":32,"c":16777217,"prf":"hmac-sha256","salt":"00"}`)
+	if _, err := bad.Decrypt("password"); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore for too many iterations, got %v", err)
+	}
+
+	if _, err := Encrypt(sk, "password", &Options{Cost: MaxScryptN * 2}, rand.Reader); !errors.Is(err, ErrInvalidKeystore) {
+		t.Fatalf("expected ErrInvalidKeystore when encrypting with too high a cost, got %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package bls
+
+import "math/bits"
+
+// msmWindow returns the Pippenger window size in bits for n points.
+func msmWindow(n int) uint {
+	c := bits.Len(uint(n))
+	if c < 4 {
+		return 2
+	}
+	return uint(c - 2)
+}
+
+// msmBits returns the bit length of the largest scalar.
+func msmBits(scalars []*FRRepr) uint {
+	max := uint(0)
+	for _, s := range scalars {
+		if l := s.BitLen(); l > max {
+			max = l
+		}
+	}
+	return max
+}
+
+// frWindow returns width bits of a scalar starting at bit start.
+func frWindow(s *FRRepr, start uint, width uint) int {
+	limb := start / 64
+	offset := start % 64
+	v := s[limb] >> offset
+	if offset+width > 64 && limb+1 < uint(len(s)) {
+		v |= s[limb+1] << (64 - offset)
+	}
+	return int(v & (1<<width - 1))
+}
+
+// MultiScalarMulG1 computes the sum of points[i] * scalars[i] using
+// Pippenger's bucket method, which is much faster than multiplying each
+// point separately for large inputs. It panics if the number of points
+// and scalars differ.
+func MultiScalarMulG1(points []*G1Projective, scalars []*FRRepr) *G1Projective {
+	if len(points) != len(scalars) {
+		panic("bls: number of points and scalars do not match")
+	}
+	c := msmWindow(len(points))
+	buckets := make([]G1Projective, 1<<c-1)
+	result := G1ProjectiveZero.Copy()
+	running := G1ProjectiveZero.Copy()
+	windowSum := G1ProjectiveZero.Copy()
+	numBits := msmBits(scalars)
+
+	for start := int((numBits+c-1)/c*c) - int(c); start >= 0; start -= int(c) {
+		for i := uint(0); i < c; i++ {
+			result.DoubleAssign()
+		}
+		for i := range buckets {
+			buckets[i].Set(G1ProjectiveZero)
+		}
+		for i, s := range scalars {
+			if b := frWindow(s, uint(start), c); b != 0 {
+				buckets[b-1].AddAssign(points[i])
+			}
+		}
+		// Sum the buckets weighted by their index with a running sum.
+		running.Set(G1ProjectiveZero)
+		windowSum.Set(G1ProjectiveZero)
+		for i := len(buckets) - 1; i >= 0; i-- {
+			running.AddAssign(&buckets[i])
+			windowSum.AddAssign(running)
+		}
+		result.AddAssign(windowSum)
+	}
+	return result
+}
+
+// MultiScalarMulG2 computes the sum of points[i] * scalars[i] using
+// Pippenger's bucket method. It panics if the number of points and
+// scalars differ.
+func MultiScalarMulG2(points []*G2Projective, scalars []*FRRepr) *G2Projective {
+	if len(points) != len(scalars) {
+		panic("bls: number of points and scalars do not match")
+	}
+	c := msmWindow(len(points))
+	buckets := make([]G2Projective, 1<<c-1)
+	result := G2ProjectiveZero.Copy()
+	running := G2ProjectiveZero.Copy()
+	windowSum := G2ProjectiveZero.Copy()
+	numBits := msmBits(scalars)
+
+	for start := int((numBits+c-1)/c*c) - int(c); start >= 0; start -= int(c) {
+		for i := uint(0); i < c; i++ {
+			result.DoubleAssign()
+		}
+		for i := range buckets {
+			buckets[i].Set(G2ProjectiveZero)
+		}
+		for i, s := range scalars {
+			if b := frWindow(s, uint(start), c); b != 0 {
+				buckets[b-1].AddAssign(points[i])
+			}
+		}
+		running.Set(G2ProjectiveZero)
+		windowSum.Set(G2ProjectiveZero)
+		for i := len(buckets) - 1; i >= 0; i-- {
+			running.AddAssign(&buckets[i])
+			windowSum.AddAssign(running)
+		}
+		result.AddAssign(windowSum)
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package bls_test
+
+import (
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+func TestMultiScalarMulG1(t *testing.T) {
+	r := NewXORShift(20)
+	for _, n := range []int{0, 1, 2, 7, 40, 130} {
+		points := make([]*bls.G1Projective, n)
+		scalars := make([]*bls.FRRepr, n)
+		expected := bls.G1ProjectiveZero.Copy()
+		for i := range points {
+			points[i], _ = bls.RandG1(r)
+			f, _ := bls.RandFR(r)
+			scalars[i] = f.ToRepr()
+			switch i % 5 {
+			case 1:
+				scalars[i] = bls.NewFRRepr(0)
+			case 2:
+				scalars[i] = bls.NewFRRepr(uint64(i))
+			case 3:
+				scalars[i] = bls.RFieldModulus.Copy()
+				scalars[i].SubNoBorrow(bls.NewFRRepr(1))
+			}
+			expected.AddAssign(points[i].MulFR(scalars[i]))
+		}
+		if !bls.MultiScalarMulG1(points, scalars).Equal(expected) {
+			t.Fatalf("multi-scalar multiplication of %d points does not match", n)
+		}
+	}
+}
+
+func TestMultiScalarMulG2(t *testing.T) {
+	r := NewXORShift(21)
+	for _, n := range []int{0, 1, 3, 20, 70} {
+		points := make([]*bls.G2Projective, n)
+		scalars := make([]*bls.FRRepr, n)
+		expected := bls.G2ProjectiveZero.Copy()
+		for i := range points {
+			points[i], _ = bls.RandG2(r)
+			f, _ := bls.RandFR(r)
+			scalars[i] = f.ToRepr()
+			if i%4 == 1 {
+				scalars[i] = bls.NewFRRepr(uint64(i))
+			}
+			expected.AddAssign(points[i].MulFR(scalars[i]))
+		}
+		if !bls.MultiScalarMulG2(points, scalars).Equals(expected) {
+			t.Fatalf("multi-scalar multiplication of %d points does not match", n)
+		}
+	}
+}
+
+func BenchmarkMultiScalarMulG1(b *testing.B) {
+	r := NewXORShift(22)
+	points := make([]*bls.G1Projective, 1000)
+	scalars := make([]*bls.FRRepr, len(points))
+	for i := range points {
+		points[i], _ = bls.RandG1(r)
+		f, _ := bls.RandFR(r)
+		scalars[i] = f.ToRepr()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bls.MultiScalarMulG1(points, scalars)
+	}
+}
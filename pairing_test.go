@@ -150,3 +150,18 @@ func BenchmarkPairing(b *testing.B) {
 		count = (count + 1) % g1MulAssignSamples
 	}
 }
+
+func TestPairingWithIdentity(t *testing.T) {
+	if !bls.Pairing(bls.G1ProjectiveZero, bls.G2ProjectiveOne).Equals(bls.FQ12One) {
+		t.Fatal("pairing with the G1 identity should be one")
+	}
+	if !bls.Pairing(bls.G1ProjectiveOne, bls.G2ProjectiveZero).Equals(bls.FQ12One) {
+		t.Fatal("pairing with the G2 identity should be one")
+	}
+	if bls.CompareTwoPairings(bls.G1ProjectiveOne, bls.G2ProjectiveZero, bls.G1ProjectiveOne, bls.G2ProjectiveOne) {
+		t.Fatal("e(g1, 0) should not equal e(g1, g2)")
+	}
+	if !bls.CompareTwoPairings(bls.G1ProjectiveZero, bls.G2ProjectiveOne, bls.G1ProjectiveOne, bls.G2ProjectiveZero) {
+		t.Fatal("e(0, g2) should equal e(g1, 0)")
+	}
+}
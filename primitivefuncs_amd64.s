@@ -1,5 +1,7 @@
 // Code generated by command: go run asm.go -out primitivefuncs_amd64.s. DO NOT EDIT.
 
+// +build !purego
+
 // func MACWithCarry(a uint64, b uint64, c uint64, carry uint64) (uint64, uint64)
 TEXT ·MACWithCarry(SB), $0-48
 	MOVQ b+8(FP), CX
@@ -1520,3 +1522,989 @@ TEXT ·SubNoBorrow(SB), $0-144
 	MOVQ R9, ret_4+128(FP)
 	MOVQ R11, ret_5+136(FP)
 	RET
+
+// func MultiplyFR(a [4]uint64, b [4]uint64) [4]uint64
+TEXT ·MultiplyFR(SB), $8-96
+	// t[0] = 0
+	XORQ CX, CX
+
+	// t[1] = 0
+	XORQ BX, BX
+
+	// t[2] = 0
+	XORQ BP, BP
+
+	// t[3] = 0
+	XORQ SI, SI
+
+	// t[4] = 0
+	XORQ DI, DI
+	MOVQ b_0+32(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * b[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * b[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * b[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * b[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ R8, R8
+	ADDQ R9, DI
+	ADCQ $0x00, R8
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ CX, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, DI
+	ADCQ $0x00, R8
+	MOVQ b_1+40(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * b[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * b[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * b[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * b[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ CX, CX
+	ADDQ R9, R8
+	ADCQ $0x00, CX
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ BX, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, R8
+	ADCQ $0x00, CX
+	MOVQ b_2+48(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * b[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * b[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * b[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * b[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ BX, BX
+	ADDQ R9, CX
+	ADCQ $0x00, BX
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ BP, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, CX
+	ADCQ $0x00, BX
+	MOVQ b_3+56(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * b[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * b[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * b[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * b[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ BP, BP
+	ADDQ R9, BX
+	ADCQ $0x00, BP
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ SI, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, BX
+	ADCQ $0x00, BP
+	MOVQ DI, AX
+	MOVQ R8, DX
+	MOVQ CX, SI
+	MOVQ BX, R9
+
+	// s = t - RFieldModulus
+	MOVQ $0xffffffff00000001, R10
+	SUBQ R10, AX
+	MOVQ $0x53bda402fffe5bfe, R10
+	SBBQ R10, DX
+	MOVQ $0x3339d80809a1d805, R10
+	SBBQ R10, SI
+	MOVQ $0x73eda753299d7d48, R10
+	SBBQ R10, R9
+	SBBQ $0x00, BP
+
+	// if no borrow occurred, t = s
+	CMOVQCC AX, DI
+	CMOVQCC DX, R8
+	CMOVQCC SI, CX
+	CMOVQCC R9, BX
+	MOVQ    DI, ret_0+64(FP)
+	MOVQ    R8, ret_1+72(FP)
+	MOVQ    CX, ret_2+80(FP)
+	MOVQ    BX, ret_3+88(FP)
+	RET
+
+// func SquareFR(a [4]uint64) [4]uint64
+TEXT ·SquareFR(SB), $8-64
+	// t[0] = 0
+	XORQ CX, CX
+
+	// t[1] = 0
+	XORQ BX, BX
+
+	// t[2] = 0
+	XORQ BP, BP
+
+	// t[3] = 0
+	XORQ SI, SI
+
+	// t[4] = 0
+	XORQ DI, DI
+	MOVQ a_0+0(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * a[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * a[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * a[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * a[0] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ R8, R8
+	ADDQ R9, DI
+	ADCQ $0x00, R8
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ CX, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, DI
+	ADCQ $0x00, R8
+	MOVQ a_1+8(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * a[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * a[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * a[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * a[1] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ CX, CX
+	ADDQ R9, R8
+	ADCQ $0x00, CX
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ BX, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ BX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BX
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, R8
+	ADCQ $0x00, CX
+	MOVQ a_2+16(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * a[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * a[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * a[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * a[2] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ BX, BX
+	ADDQ R9, CX
+	ADCQ $0x00, BX
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ BP, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ BP, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, BP
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, CX
+	ADCQ $0x00, BX
+	MOVQ a_3+24(FP), R10
+
+	// carry = 0
+	XORQ R9, R9
+	MOVQ a_0+0(FP), AX
+
+	// (carry, t[0]) = t[0] + a[0] * a[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+	MOVQ a_1+8(FP), AX
+
+	// (carry, t[1]) = t[1] + a[1] * a[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+	MOVQ a_2+16(FP), AX
+
+	// (carry, t[2]) = t[2] + a[2] * a[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+	MOVQ a_3+24(FP), AX
+
+	// (carry, t[3]) = t[3] + a[3] * a[3] + carry
+	MOVQ AX, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+
+	// (t[5], t[4]) = t[4] + carry
+	XORQ BP, BP
+	ADDQ R9, BX
+	ADCQ $0x00, BP
+
+	// m = (t[0] * 18446744069414584319) & 0xFFFFFFFFFFFFFFFF
+	MOVQ  $0xfffffffeffffffff, R10
+	IMULQ SI, R10
+
+	// carry = ((t[0] + RFieldModulus[0] * m) >> 64) & 0xFFFFFFFFFFFFFFFF
+	XORQ R9, R9
+	MOVQ $0xffffffff00000001, AX
+	MULQ R10
+	ADDQ SI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, SI
+
+	// (carry, t[1]) = t[1] + RFieldModulus[1] * m + carry
+	MOVQ $0x53bda402fffe5bfe, AX
+	MULQ R10
+	ADDQ DI, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, DI
+
+	// (carry, t[2]) = t[2] + RFieldModulus[2] * m + carry
+	MOVQ $0x3339d80809a1d805, AX
+	MULQ R10
+	ADDQ R8, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, R8
+
+	// (carry, t[3]) = t[3] + RFieldModulus[3] * m + carry
+	MOVQ $0x73eda753299d7d48, AX
+	MULQ R10
+	ADDQ CX, AX
+	ADCQ $0x00, DX
+	ADDQ R9, AX
+	ADCQ $0x00, DX
+	MOVQ DX, R9
+	MOVQ AX, CX
+
+	// (t[5], t[4]) = t[5] + t[4] + carry
+	ADDQ R9, BX
+	ADCQ $0x00, BP
+	MOVQ DI, AX
+	MOVQ R8, DX
+	MOVQ CX, SI
+	MOVQ BX, R9
+
+	// s = t - RFieldModulus
+	MOVQ $0xffffffff00000001, R10
+	SUBQ R10, AX
+	MOVQ $0x53bda402fffe5bfe, R10
+	SBBQ R10, DX
+	MOVQ $0x3339d80809a1d805, R10
+	SBBQ R10, SI
+	MOVQ $0x73eda753299d7d48, R10
+	SBBQ R10, R9
+	SBBQ $0x00, BP
+
+	// if no borrow occurred, t = s
+	CMOVQCC AX, DI
+	CMOVQCC DX, R8
+	CMOVQCC SI, CX
+	CMOVQCC R9, BX
+	MOVQ    DI, ret_0+32(FP)
+	MOVQ    R8, ret_1+40(FP)
+	MOVQ    CX, ret_2+48(FP)
+	MOVQ    BX, ret_3+56(FP)
+	RET
+
+// func AddFR(a [4]uint64, b [4]uint64) [4]uint64
+TEXT ·AddFR(SB), $8-96
+	MOVQ a_0+0(FP), AX
+	MOVQ b_0+32(FP), CX
+	MOVQ a_1+8(FP), DX
+	MOVQ b_1+40(FP), BX
+	MOVQ a_2+16(FP), BP
+	MOVQ b_2+48(FP), SI
+	MOVQ a_3+24(FP), DI
+	MOVQ b_3+56(FP), R8
+
+	// a = a + b
+	ADDQ CX, AX
+	ADCQ BX, DX
+	ADCQ SI, BP
+	ADCQ R8, DI
+	XORQ R9, R9
+	MOVQ AX, CX
+	MOVQ DX, BX
+	MOVQ BP, SI
+	MOVQ DI, R8
+
+	// s = t - RFieldModulus
+	MOVQ $0xffffffff00000001, R10
+	SUBQ R10, CX
+	MOVQ $0x53bda402fffe5bfe, R10
+	SBBQ R10, BX
+	MOVQ $0x3339d80809a1d805, R10
+	SBBQ R10, SI
+	MOVQ $0x73eda753299d7d48, R10
+	SBBQ R10, R8
+	SBBQ $0x00, R9
+
+	// if no borrow occurred, t = s
+	CMOVQCC CX, AX
+	CMOVQCC BX, DX
+	CMOVQCC SI, BP
+	CMOVQCC R8, DI
+	MOVQ    AX, ret_0+64(FP)
+	MOVQ    DX, ret_1+72(FP)
+	MOVQ    BP, ret_2+80(FP)
+	MOVQ    DI, ret_3+88(FP)
+	RET
+
+// func SubFR(a [4]uint64, b [4]uint64) [4]uint64
+TEXT ·SubFR(SB), $8-96
+	MOVQ a_0+0(FP), AX
+	MOVQ b_0+32(FP), CX
+	MOVQ a_1+8(FP), DX
+	MOVQ b_1+40(FP), BX
+	MOVQ a_2+16(FP), BP
+	MOVQ b_2+48(FP), SI
+	MOVQ a_3+24(FP), DI
+	MOVQ b_3+56(FP), R8
+	MOVQ $0x00000000, R9
+
+	// a = a - b
+	SUBQ CX, AX
+	SBBQ BX, DX
+	SBBQ SI, BP
+	SBBQ R8, DI
+
+	// mask = 0xFFFFFFFFFFFFFFFF if a borrow occurred, otherwise 0
+	SBBQ R9, R9
+	MOVQ $0xffffffff00000001, CX
+	ANDQ R9, CX
+	MOVQ $0x53bda402fffe5bfe, BX
+	ANDQ R9, BX
+	MOVQ $0x3339d80809a1d805, SI
+	ANDQ R9, SI
+	MOVQ $0x73eda753299d7d48, R8
+	ANDQ R9, R8
+
+	// a = a + (RFieldModulus & mask)
+	ADDQ CX, AX
+	ADCQ BX, DX
+	ADCQ SI, BP
+	ADCQ R8, DI
+	MOVQ AX, ret_0+64(FP)
+	MOVQ DX, ret_1+72(FP)
+	MOVQ BP, ret_2+80(FP)
+	MOVQ DI, ret_3+88(FP)
+	RET
@@ -0,0 +1,127 @@
+package bls
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// These tests check the exported primitive functions, which are written in
+// assembly on amd64, against the portable implementations. Under the purego
+// tag or on other architectures both sides are the portable implementations.
+
+const differentialIterations = 100000
+
+var edgeLimbs = []uint64{0, 1, 2, 0x7fffffffffffffff, 0x8000000000000000, 0xfffffffffffffffe, 0xffffffffffffffff}
+
+// randLimb returns a random limb biased towards values that exercise
+// carries and borrows.
+func randLimb(r *rand.Rand) uint64 {
+	if r.Intn(4) == 0 {
+		return edgeLimbs[r.Intn(len(edgeLimbs))]
+	}
+	return r.Uint64()
+}
+
+func randFQLimbs(r *rand.Rand) [6]uint64 {
+	var out [6]uint64
+	for i := range out {
+		out[i] = randLimb(r)
+	}
+	if r.Intn(2) == 0 {
+		out[5] &= 0x0fffffffffffffff
+		f := FQ{n: out}
+		f.reduceAssign()
+		out = f.n
+	}
+	return out
+}
+
+func randFRLimbs(r *rand.Rand) [4]uint64 {
+	for {
+		var out FRRepr
+		for i := range out {
+			out[i] = randLimb(r)
+		}
+		out[3] &= 0x7fffffffffffffff
+		if out.Cmp(RFieldModulus) < 0 {
+			return out
+		}
+	}
+}
+
+func TestDifferentialCarryPrimitives(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < differentialIterations; i++ {
+		a, b, c := randLimb(r), randLimb(r), randLimb(r)
+		carry := randLimb(r)
+		bit := uint64(r.Intn(2))
+
+		lo, hi := MACWithCarry(a, b, c, carry)
+		expectedLo, expectedHi := macWithCarryGeneric(a, b, c, carry)
+		if lo != expectedLo || hi != expectedHi {
+			t.Fatalf("MACWithCarry(%x, %x, %x, %x) = (%x, %x), expected (%x, %x)", a, b, c, carry, lo, hi, expectedLo, expectedHi)
+		}
+
+		out, outCarry := AddWithCarry(a, b, bit)
+		expectedOut, expectedCarry := addWithCarryGeneric(a, b, bit)
+		if out != expectedOut || outCarry != expectedCarry {
+			t.Fatalf("AddWithCarry(%x, %x, %d) = (%x, %d), expected (%x, %d)", a, b, bit, out, outCarry, expectedOut, expectedCarry)
+		}
+
+		out, outBorrow := SubWithBorrow(a, b, bit)
+		expectedOut, expectedBorrow := subWithBorrowGeneric(a, b, bit)
+		if out != expectedOut || outBorrow != expectedBorrow {
+			t.Fatalf("SubWithBorrow(%x, %x, %d) = (%x, %d), expected (%x, %d)", a, b, bit, out, outBorrow, expectedOut, expectedBorrow)
+		}
+	}
+}
+
+func TestDifferentialFQPrimitives(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < differentialIterations; i++ {
+		a := randFQLimbs(r)
+		b := randFQLimbs(r)
+
+		hi, lo := MultiplyFQRepr(a, b)
+		expectedHi, expectedLo := multiplyFQReprGeneric(a, b)
+		if hi != expectedHi || lo != expectedLo {
+			t.Fatalf("MultiplyFQRepr(%x, %x) = (%x, %x), expected (%x, %x)", a, b, hi, lo, expectedHi, expectedLo)
+		}
+
+		if out, expected := MontReduce(hi, lo), montReduceGeneric(hi, lo); out != expected {
+			t.Fatalf("MontReduce(%x, %x) = %x, expected %x", hi, lo, out, expected)
+		}
+
+		if out, expected := AddNoCarry(a, b), addNoCarryGeneric(a, b); out != expected {
+			t.Fatalf("AddNoCarry(%x, %x) = %x, expected %x", a, b, out, expected)
+		}
+
+		if out, expected := SubNoBorrow(a, b), subNoBorrowGeneric(a, b); out != expected {
+			t.Fatalf("SubNoBorrow(%x, %x) = %x, expected %x", a, b, out, expected)
+		}
+	}
+}
+
+func TestDifferentialFRPrimitives(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < differentialIterations; i++ {
+		a := randFRLimbs(r)
+		b := randFRLimbs(r)
+
+		if out, expected := MultiplyFR(a, b), multiplyFRGeneric(a, b); out != expected {
+			t.Fatalf("MultiplyFR(%x, %x) = %x, expected %x", a, b, out, expected)
+		}
+
+		if out, expected := SquareFR(a), squareFRGeneric(a); out != expected {
+			t.Fatalf("SquareFR(%x) = %x, expected %x", a, out, expected)
+		}
+
+		if out, expected := AddFR(a, b), addFRGeneric(a, b); out != expected {
+			t.Fatalf("AddFR(%x, %x) = %x, expected %x", a, b, out, expected)
+		}
+
+		if out, expected := SubFR(a, b), subFRGeneric(a, b); out != expected {
+			t.Fatalf("SubFR(%x, %x) = %x, expected %x", a, b, out, expected)
+		}
+	}
+}
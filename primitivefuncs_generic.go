@@ -0,0 +1,144 @@
+package bls
+
+import "math/bits"
+
+// This file contains the portable implementations of the primitive
+// functions. They are used directly when assembly is not available and
+// are always compiled so they can be checked against the assembly.
+
+const montInvFQ = uint64(0x89f3fffcfffcfffd)
+
+const montInvFR = uint64(0xfffffffeffffffff)
+
+func macWithCarryGeneric(a, b, c, carry uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(b, c)
+	lo, c0 := bits.Add64(lo, a, 0)
+	lo, c1 := bits.Add64(lo, carry, 0)
+	// b * c + a + carry < 2^128, so this never overflows
+	return lo, hi + c0 + c1
+}
+
+func addWithCarryGeneric(a, b, carry uint64) (uint64, uint64) {
+	out, c0 := bits.Add64(a, b, 0)
+	out, c1 := bits.Add64(out, carry, 0)
+	return out, c0 + c1
+}
+
+func subWithBorrowGeneric(a, b, borrow uint64) (uint64, uint64) {
+	out, b0 := bits.Sub64(a, b, 0)
+	out, b1 := bits.Sub64(out, borrow, 0)
+	return out, b0 | b1
+}
+
+func multiplyFQReprGeneric(a, b [6]uint64) (hi [6]uint64, lo [6]uint64) {
+	var t [12]uint64
+	for i := 0; i < 6; i++ {
+		carry := uint64(0)
+		for j := 0; j < 6; j++ {
+			t[i+j], carry = macWithCarryGeneric(t[i+j], a[i], b[j], carry)
+		}
+		t[i+6] = carry
+	}
+	copy(lo[:], t[:6])
+	copy(hi[:], t[6:])
+	return hi, lo
+}
+
+func montReduceGeneric(hi, lo [6]uint64) [6]uint64 {
+	var t [12]uint64
+	copy(t[:6], lo[:])
+	copy(t[6:], hi[:])
+
+	carryOver := uint64(0)
+	for i := 0; i < 6; i++ {
+		k := t[i] * montInvFQ
+		carry := uint64(0)
+		for j := 0; j < 6; j++ {
+			t[i+j], carry = macWithCarryGeneric(t[i+j], k, QFieldModulus[j], carry)
+		}
+		t[i+6], carryOver = addWithCarryGeneric(t[i+6], carry, carryOver)
+	}
+
+	var out [6]uint64
+	copy(out[:], t[6:])
+	return out
+}
+
+func addNoCarryGeneric(a, b [6]uint64) (out [6]uint64) {
+	carry := uint64(0)
+	for i := 0; i < 6; i++ {
+		out[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	return out
+}
+
+func subNoBorrowGeneric(a, b [6]uint64) (out [6]uint64) {
+	borrow := uint64(0)
+	for i := 0; i < 6; i++ {
+		out[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+	return out
+}
+
+// reduceOnceFR subtracts the modulus from the 320-bit value hi || t if it is
+// greater than or equal to the modulus.
+func reduceOnceFR(t [4]uint64, hi uint64) [4]uint64 {
+	var s [4]uint64
+	borrow := uint64(0)
+	for i := 0; i < 4; i++ {
+		s[i], borrow = bits.Sub64(t[i], RFieldModulus[i], borrow)
+	}
+	_, borrow = bits.Sub64(hi, 0, borrow)
+	if borrow != 0 {
+		return t
+	}
+	return s
+}
+
+func multiplyFRGeneric(a, b [4]uint64) [4]uint64 {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		carry := uint64(0)
+		for j := 0; j < 4; j++ {
+			t[j], carry = macWithCarryGeneric(t[j], a[j], b[i], carry)
+		}
+		t[4], t[5] = bits.Add64(t[4], carry, 0)
+
+		k := t[0] * montInvFR
+		_, carry = macWithCarryGeneric(t[0], k, RFieldModulus[0], 0)
+		for j := 1; j < 4; j++ {
+			t[j-1], carry = macWithCarryGeneric(t[j], k, RFieldModulus[j], carry)
+		}
+		t[3], carry = bits.Add64(t[4], carry, 0)
+		t[4] = t[5] + carry
+	}
+	return reduceOnceFR([4]uint64{t[0], t[1], t[2], t[3]}, t[4])
+}
+
+func squareFRGeneric(a [4]uint64) [4]uint64 {
+	return multiplyFRGeneric(a, a)
+}
+
+func addFRGeneric(a, b [4]uint64) [4]uint64 {
+	var out [4]uint64
+	carry := uint64(0)
+	for i := 0; i < 4; i++ {
+		out[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+	return reduceOnceFR(out, carry)
+}
+
+func subFRGeneric(a, b [4]uint64) [4]uint64 {
+	var out [4]uint64
+	borrow := uint64(0)
+	for i := 0; i < 4; i++ {
+		out[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+	// add the modulus back if the subtraction underflowed
+	mask := -borrow
+	carry := uint64(0)
+	for i := 0; i < 4; i++ {
+		out[i], carry = bits.Add64(out[i], RFieldModulus[i]&mask, carry)
+	}
+	return out
+}
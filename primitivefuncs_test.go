@@ -283,3 +283,92 @@ func TestRandomMultiplyFQRepr(t *testing.T) {
 		}
 	}
 }
+
+var rInvFR = new(big.Int).ModInverse(new(big.Int).Lsh(big.NewInt(1), 256), bls.RFieldModulus.ToBig())
+
+func randFRRepr(t *testing.T, r *XORShift) (bls.FRRepr, *big.Int) {
+	n, err := rand.Int(r, bls.RFieldModulus.ToBig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := bls.FRReprFromBigInt(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *f, n
+}
+
+func TestRandomMultiplyFR(t *testing.T) {
+	r := NewXORShift(1)
+	modulus := bls.RFieldModulus.ToBig()
+
+	for i := 0; i < 100000; i++ {
+		a, aBig := randFRRepr(t, r)
+		b, bBig := randFRRepr(t, r)
+
+		expected := new(big.Int).Mul(aBig, bBig)
+		expected.Mul(expected, rInvFR)
+		expected.Mod(expected, modulus)
+
+		if bls.FRRepr(bls.MultiplyFR(a, b)).ToBig().Cmp(expected) != 0 {
+			t.Fatalf("MultiplyFR(%s, %s) does not match big int multiplication", a, b)
+		}
+
+		expected.Mul(aBig, aBig)
+		expected.Mul(expected, rInvFR)
+		expected.Mod(expected, modulus)
+
+		if bls.FRRepr(bls.SquareFR(a)).ToBig().Cmp(expected) != 0 {
+			t.Fatalf("SquareFR(%s) does not match big int multiplication", a)
+		}
+	}
+}
+
+func TestRandomAddSubFR(t *testing.T) {
+	r := NewXORShift(1)
+	modulus := bls.RFieldModulus.ToBig()
+
+	for i := 0; i < 100000; i++ {
+		a, aBig := randFRRepr(t, r)
+		b, bBig := randFRRepr(t, r)
+
+		expected := new(big.Int).Add(aBig, bBig)
+		expected.Mod(expected, modulus)
+		if bls.FRRepr(bls.AddFR(a, b)).ToBig().Cmp(expected) != 0 {
+			t.Fatalf("AddFR(%s, %s) does not match big int addition", a, b)
+		}
+
+		expected.Sub(aBig, bBig)
+		expected.Mod(expected, modulus)
+		if bls.FRRepr(bls.SubFR(a, b)).ToBig().Cmp(expected) != 0 {
+			t.Fatalf("SubFR(%s, %s) does not match big int subtraction", a, b)
+		}
+	}
+}
+
+func TestFRKernelEdgeCases(t *testing.T) {
+	rMinusOne := *bls.RFieldModulus
+	rMinusOne[0]--
+	zero := [4]uint64{}
+
+	if bls.AddFR(rMinusOne, [4]uint64{1, 0, 0, 0}) != zero {
+		t.Fatal("(r - 1) + 1 should equal zero")
+	}
+	if bls.FRRepr(bls.SubFR(zero, [4]uint64{1, 0, 0, 0})) != rMinusOne {
+		t.Fatal("0 - 1 should equal r - 1")
+	}
+	if bls.MultiplyFR(rMinusOne, zero) != zero {
+		t.Fatal("(r - 1) * 0 should equal zero")
+	}
+}
+
+func BenchmarkMultiplyFR(b *testing.B) {
+	r := NewXORShift(1)
+	n, _ := rand.Int(r, bls.RFieldModulus.ToBig())
+	f, _ := bls.FRReprFromBigInt(n)
+	out := *f
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out = bls.MultiplyFR(out, *f)
+	}
+}
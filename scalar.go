@@ -0,0 +1,335 @@
+package bls
+
+import (
+	"crypto/rand"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// Scalar is an element in the R field stored by value in
+// Montgomery form.
+type Scalar struct {
+	n FRRepr
+}
+
+// ScalarZero is the zero Scalar element.
+var ScalarZero = ScalarReprToScalarRaw(FRRepr{0, 0, 0, 0})
+
+// ScalarOne is the one Scalar element.
+var ScalarOne = ScalarReprToScalar(FRRepr{1, 0, 0, 0})
+
+// Copy creates a copy of the field element.
+func (s Scalar) Copy() Scalar {
+	return s
+}
+
+// IsValid checks if the element is valid.
+func (s *Scalar) IsValid() bool {
+	return s.n.Cmp(RFieldModulus) < 0
+}
+
+// ScalarReprToScalar converts an FRRepr to a Scalar, returning
+// zero if the representation is not less than the modulus.
+func ScalarReprToScalar(o FRRepr) Scalar {
+	r := Scalar{n: o}
+	if r.IsValid() {
+		r.n = MultiplyFR(r.n, *FRR2)
+		return r
+	}
+	return Scalar{}
+}
+
+// ScalarReprToScalarRaw gets a Scalar without converting to
+// montgomery form.
+func ScalarReprToScalarRaw(o FRRepr) Scalar {
+	return Scalar{n: o}
+}
+
+// ScalarFromFR converts an FR element to a Scalar.
+func ScalarFromFR(f *FR) Scalar {
+	return Scalar{n: *f.n}
+}
+
+// ToFR converts the Scalar to a newly allocated FR element.
+func (s Scalar) ToFR() *FR {
+	n := s.n
+	return &FR{n: &n}
+}
+
+// ToScalar converts the FR element to a Scalar.
+func (f *FR) ToScalar() Scalar {
+	return ScalarFromFR(f)
+}
+
+// Zeroize overwrites the limbs of the element with zeros.
+func (s *Scalar) Zeroize() {
+	for i := range s.n {
+		s.n[i] = 0
+	}
+}
+
+// AddAssign adds a field element to this one.
+func (s *Scalar) AddAssign(other Scalar) {
+	s.n = AddFR(s.n, other.n)
+}
+
+// MulAssign multiplies a field element by this one.
+func (s *Scalar) MulAssign(other Scalar) {
+	s.n = MultiplyFR(s.n, other.n)
+}
+
+// SubAssign subtracts a field element from this one.
+func (s *Scalar) SubAssign(other Scalar) {
+	s.n = SubFR(s.n, other.n)
+}
+
+// DivAssign divides the field element by another.
+func (s *Scalar) DivAssign(other Scalar) {
+	otherInv, _ := other.Inverse()
+	s.MulAssign(otherInv)
+}
+
+// Exp raises the element to a specific power.
+func (s Scalar) Exp(n FRRepr) Scalar {
+	iter := NewBitIterator(n[:])
+	res := ScalarOne.Copy()
+	foundOne := false
+	next, done := iter.Next()
+	for !done {
+		if foundOne {
+			res.SquareAssign()
+		} else {
+			foundOne = next
+		}
+		if next {
+			res.MulAssign(s)
+		}
+		next, done = iter.Next()
+	}
+	return res
+}
+
+// Equals checks equality of two field elements.
+func (s Scalar) Equals(other Scalar) bool {
+	return s.n == other.n
+}
+
+// NegAssign gets the negative value of the field element mod RFieldModulus.
+func (s *Scalar) NegAssign() {
+	s.n = SubFR([4]uint64{}, s.n)
+}
+
+func (s Scalar) String() string {
+	return fmt.Sprintf("Scalar(0x%s)", s.ToRepr().String())
+}
+
+// Cmp compares this field element to another.
+func (s Scalar) Cmp(other Scalar) int {
+	r1 := s.ToRepr()
+	r2 := other.ToRepr()
+	return r1.Cmp(&r2)
+}
+
+// DoubleAssign doubles the element.
+func (s *Scalar) DoubleAssign() {
+	s.n = AddFR(s.n, s.n)
+}
+
+// IsZero checks if the field element is zero.
+func (s Scalar) IsZero() bool {
+	return s.n.IsZero()
+}
+
+// SquareAssign squares a field element.
+func (s *Scalar) SquareAssign() {
+	s.n = SquareFR(s.n)
+}
+
+// frTwoAdicity is the largest S such that 2^S divides r - 1.
+const frTwoAdicity = 32
+
+// frTMinus1Over2 is (t - 1) / 2 where r - 1 = 2^S * t.
+var frTMinus1Over2 = FRRepr{0x7fff2dff7fffffff, 0x04d0ec02a9ded201, 0x94cebea4199cec04, 0x0000000039f6d3a9}
+
+// frRootOfUnity is 7^t, a primitive 2^S-th root of unity, in
+// Montgomery form.
+var frRootOfUnity = ScalarReprToScalarRaw(FRRepr{0xb9b58d8c5f0e466a, 0x5b1b4c801819d7ec, 0x0af53ae352a31e64, 0x5bf3adda19e9b27b})
+
+// Sqrt calculates the square root of the field element.
+func (s Scalar) Sqrt() (Scalar, bool) {
+	// Tonelli-Shanks algorithm for r mod 16 = 1
+	// https://eprint.iacr.org/2012/685.pdf (page 12, algorithm 5)
+
+	if s.IsZero() {
+		return Scalar{}, true
+	}
+
+	w := s.Exp(frTMinus1Over2)
+	v := frTwoAdicity
+	x := s.Copy()
+	x.MulAssign(w)
+	b := x.Copy()
+	b.MulAssign(w)
+	z := frRootOfUnity.Copy()
+
+	for !b.Equals(ScalarOne) {
+		k := 0
+		b2k := b.Copy()
+		for !b2k.Equals(ScalarOne) {
+			b2k.SquareAssign()
+			k++
+		}
+
+		if k == v {
+			// b has order 2^S so s is not a quadratic residue
+			return Scalar{}, false
+		}
+
+		w = z.Copy()
+		for j := 0; j < v-k-1; j++ {
+			w.SquareAssign()
+		}
+
+		z = w.Copy()
+		z.SquareAssign()
+		b.MulAssign(z)
+		x.MulAssign(w)
+		v = k
+	}
+
+	return x, true
+}
+
+// IsSquare checks if the element is a quadratic residue or zero.
+func (s *Scalar) IsSquare() bool {
+	return s.Legendre() != LegendreQuadraticNonResidue
+}
+
+// Inverse finds the inverse of the field element.
+func (s Scalar) Inverse() (Scalar, bool) {
+	if s.IsZero() {
+		return Scalar{}, false
+	}
+	u := s.n
+	v := *RFieldModulus
+	b := ScalarReprToScalarRaw(*FRR2)
+	c := ScalarZero.Copy()
+
+	one := FRRepr{1, 0, 0, 0}
+	for u != one && v != one {
+		for u.IsEven() {
+			u.Div2()
+			if !b.n.IsEven() {
+				b.n.AddNoCarry(RFieldModulus)
+			}
+			b.n.Div2()
+		}
+
+		for v.IsEven() {
+			v.Div2()
+			if !c.n.IsEven() {
+				c.n.AddNoCarry(RFieldModulus)
+			}
+			c.n.Div2()
+		}
+
+		if u.Cmp(&v) >= 0 {
+			u.SubNoBorrow(&v)
+			b.SubAssign(c)
+		} else {
+			v.SubNoBorrow(&u)
+			c.SubAssign(b)
+		}
+	}
+	if u == one {
+		return b, true
+	}
+	return c, true
+}
+
+// Parity checks if the point is greater than the point negated.
+func (s Scalar) Parity() bool {
+	neg := s.Copy()
+	neg.NegAssign()
+	return s.Cmp(neg) > 0
+}
+
+// MulBits multiplies the number by a big number.
+func (s Scalar) MulBits(b *FRRepr) Scalar {
+	res := ScalarZero.Copy()
+	for i := int(b.BitLen()) - 1; i >= 0; i-- {
+		res.DoubleAssign()
+		if b.Bit(uint(i)) {
+			res.AddAssign(s)
+		}
+	}
+	return res
+}
+
+// MulBytes multiplies the number by some bytes.
+func (s Scalar) MulBytes(b []byte) Scalar {
+	res := ScalarZero.Copy()
+	for i := uint(0); i < uint(len(b)*8); i++ {
+		res.DoubleAssign()
+		if b[i/8]&(1<<(i%8)) != 0 {
+			res.AddAssign(s)
+		}
+	}
+	return res
+}
+
+// HashScalar calculates a new Scalar value based on a hash.
+func HashScalar(hasher hash.Hash) Scalar {
+	digest := hasher.Sum(nil)
+	return ScalarOne.MulBytes(digest)
+}
+
+// Legendre gets the legendre symbol of the element.
+func (s *Scalar) Legendre() LegendreSymbol {
+	o := s.Exp(*rMinus1Over2)
+	if o.IsZero() {
+		return LegendreZero
+	} else if o.Equals(ScalarOne) {
+		return LegendreQuadraticResidue
+	} else {
+		return LegendreQuadraticNonResidue
+	}
+}
+
+// ToRepr gets the 256-bit representation of the field element.
+func (s *Scalar) ToRepr() FRRepr {
+	return MultiplyFR(s.n, [4]uint64{1, 0, 0, 0})
+}
+
+// Bytes gets the big-endian representation of the Scalar.
+func (s *Scalar) Bytes() [32]byte {
+	return s.ToRepr().Bytes()
+}
+
+// RandScalar generates a random Scalar element.
+func RandScalar(reader io.Reader) (Scalar, error) {
+	r, err := rand.Int(reader, RFieldModulus.ToBig())
+	if err != nil {
+		return Scalar{}, err
+	}
+	b, _ := FRReprFromBigInt(r)
+	return ScalarReprToScalar(*b), nil
+}
+
+// RandScalarNonZero generates a uniformly random non-zero Scalar by
+// rejection sampling 255-bit values read from the reader.
+func RandScalarNonZero(reader io.Reader) (Scalar, error) {
+	var b [32]byte
+	for {
+		if _, err := io.ReadFull(reader, b[:]); err != nil {
+			return Scalar{}, err
+		}
+		// r is a 255-bit number
+		b[0] &= 0x7f
+		repr := FRReprFromBytes(b)
+		if !repr.IsZero() && repr.Cmp(RFieldModulus) < 0 {
+			return ScalarReprToScalar(*repr), nil
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package bls_test
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+func scalarToBig(s bls.Scalar) *big.Int {
+	return s.ToRepr().ToBig()
+}
+
+func TestScalarArithmeticMatchesBig(t *testing.T) {
+	r := NewXORShift(1)
+	modulus := bls.RFieldModulus.ToBig()
+	for i := 0; i < 200; i++ {
+		a, _ := bls.RandScalar(r)
+		b, _ := bls.RandScalar(r)
+		aBig := scalarToBig(a)
+		bBig := scalarToBig(b)
+
+		sum := a.Copy()
+		sum.AddAssign(b)
+		expected := new(big.Int).Add(aBig, bBig)
+		if scalarToBig(sum).Cmp(expected.Mod(expected, modulus)) != 0 {
+			t.Fatal("scalar addition does not match big.Int")
+		}
+
+		diff := a.Copy()
+		diff.SubAssign(b)
+		expected = new(big.Int).Sub(aBig, bBig)
+		if scalarToBig(diff).Cmp(expected.Mod(expected, modulus)) != 0 {
+			t.Fatal("scalar subtraction does not match big.Int")
+		}
+
+		product := a.Copy()
+		product.MulAssign(b)
+		expected = new(big.Int).Mul(aBig, bBig)
+		if scalarToBig(product).Cmp(expected.Mod(expected, modulus)) != 0 {
+			t.Fatal("scalar multiplication does not match big.Int")
+		}
+
+		square := a.Copy()
+		square.SquareAssign()
+		expected = new(big.Int).Mul(aBig, aBig)
+		if scalarToBig(square).Cmp(expected.Mod(expected, modulus)) != 0 {
+			t.Fatal("scalar squaring does not match big.Int")
+		}
+
+		neg := a.Copy()
+		neg.NegAssign()
+		expected = new(big.Int).Neg(aBig)
+		if scalarToBig(neg).Cmp(expected.Mod(expected, modulus)) != 0 {
+			t.Fatal("scalar negation does not match big.Int")
+		}
+
+		bRepr := b.ToRepr()
+		if !a.MulBits(&bRepr).Equals(product) {
+			t.Fatal("MulBits does not match multiplication")
+		}
+
+		exp := a.Exp(bRepr)
+		expected = new(big.Int).Exp(aBig, bBig, modulus)
+		if scalarToBig(exp).Cmp(expected) != 0 {
+			t.Fatal("scalar exponentiation does not match big.Int")
+		}
+	}
+}
+
+func TestScalarInverse(t *testing.T) {
+	r := NewXORShift(2)
+	for i := 0; i < 100; i++ {
+		a, _ := bls.RandScalar(r)
+		inv, ok := a.Inverse()
+		if !ok {
+			t.Fatal("random scalar should be invertible")
+		}
+		a.MulAssign(inv)
+		if !a.Equals(bls.ScalarOne) {
+			t.Fatal("multiplication with inverse must be one")
+		}
+	}
+
+	if _, ok := bls.ScalarZero.Inverse(); ok {
+		t.Fatal("zero should not be invertible")
+	}
+}
+
+func TestScalarNegZero(t *testing.T) {
+	z := bls.ScalarZero.Copy()
+	z.NegAssign()
+	if !z.IsZero() {
+		t.Fatal("negation of zero should be zero")
+	}
+}
+
+func TestScalarFRConversion(t *testing.T) {
+	r := NewXORShift(3)
+	for i := 0; i < 50; i++ {
+		f, _ := bls.RandFR(r)
+		s := bls.ScalarFromFR(f)
+		if s.ToRepr() != *f.ToRepr() {
+			t.Fatal("scalar converted from FR has a different value")
+		}
+		if s.Bytes() != f.Bytes() {
+			t.Fatal("scalar converted from FR has different bytes")
+		}
+
+		back := s.ToFR()
+		if !back.Equals(f) {
+			t.Fatal("round trip through Scalar changed the FR")
+		}
+
+		// the converted values must not share storage
+		back.DoubleAssign()
+		if !s.ToFR().Equals(f) {
+			t.Fatal("FR returned by ToFR aliases the scalar")
+		}
+	}
+}
+
+func TestScalarReprToScalarInvalid(t *testing.T) {
+	if !bls.ScalarReprToScalar(*bls.RFieldModulus).IsZero() {
+		t.Fatal("a representation equal to the modulus should not be accepted")
+	}
+}
+
+func TestHashScalarMatchesHashFR(t *testing.T) {
+	h := sha256.New()
+	h.Write([]byte("test"))
+	s := bls.HashScalar(h)
+	if s.ToRepr() != *bls.HashFR(h).ToRepr() {
+		t.Fatal("HashScalar should match HashFR")
+	}
+}
+
+func TestScalarDoesNotAllocate(t *testing.T) {
+	a := bls.ScalarOne.Copy()
+	a.DoubleAssign()
+	b := a.Copy()
+	allocs := testing.AllocsPerRun(100, func() {
+		a.MulAssign(b)
+		a.AddAssign(b)
+		a.SubAssign(b)
+		a.SquareAssign()
+	})
+	if allocs != 0 {
+		t.Fatalf("expected scalar arithmetic not to allocate, got %f allocations", allocs)
+	}
+}
+
+func TestScalarZeroize(t *testing.T) {
+	s, _ := bls.RandScalar(NewXORShift(4))
+	s.Zeroize()
+	if !s.IsZero() {
+		t.Fatal("zeroized scalar should be zero")
+	}
+
+	f, _ := bls.RandFR(NewXORShift(4))
+	f.Zeroize()
+	if !f.IsZero() {
+		t.Fatal("zeroized FR should be zero")
+	}
+}
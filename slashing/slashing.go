@@ -0,0 +1,433 @@
+// Package slashing implements a file-backed EIP-3076 slashing protection
+// store that refuses to sign conflicting blocks and attestations.
+package slashing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/phoreproject/bls"
+	"github.com/phoreproject/bls/g1pubs"
+)
+
+// InterchangeFormatVersion is the EIP-3076 interchange format version
+// read and written by the store.
+const InterchangeFormatVersion = "5"
+
+var (
+	// ErrSlashableBlock is returned when signing a block could lead to
+	// the validator being slashed.
+	ErrSlashableBlock = errors.New("refusing to sign slashable block")
+
+	// ErrSlashableAttestation is returned when signing an attestation
+	// could lead to the validator being slashed.
+	ErrSlashableAttestation = errors.New("refusing to sign slashable attestation")
+
+	// ErrGenesisValidatorsRootMismatch is returned when interchange data
+	// is for a different chain than the store.
+	ErrGenesisValidatorsRootMismatch = errors.New("genesis validators root does not match")
+
+	// ErrInvalidInterchange is returned when interchange data is malformed.
+	ErrInvalidInterchange = errors.New("invalid slashing protection interchange")
+)
+
+// Interchange is the EIP-3076 slashing protection interchange format.
+type Interchange struct {
+	Metadata InterchangeMetadata `json:"metadata"`
+	Data     []InterchangeData   `json:"data"`
+}
+
+// InterchangeMetadata is the metadata of an interchange file.
+type InterchangeMetadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// InterchangeData is the signing history of one public key.
+type InterchangeData struct {
+	Pubkey             string              `json:"pubkey"`
+	SignedBlocks       []SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []SignedAttestation `json:"signed_attestations"`
+}
+
+// SignedBlock is a signed block. The signing root is optional.
+type SignedBlock struct {
+	Slot        uint64 `json:"slot,string"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// SignedAttestation is a signed attestation. The signing root is
+// optional.
+type SignedAttestation struct {
+	SourceEpoch uint64 `json:"source_epoch,string"`
+	TargetEpoch uint64 `json:"target_epoch,string"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// Store is a slashing protection store backed by a file in the
+// interchange format. It keeps only the highest signed slot and epochs of
+// each public key, so its size does not grow with the number of
+// signatures. It is safe for concurrent use.
+type Store struct {
+	path                  string
+	genesisValidatorsRoot string
+
+	lock    sync.Mutex
+	history map[string]*InterchangeData
+}
+
+func encodeRoot(root [32]byte) string {
+	return string(bls.EncodeHex(root[:]))
+}
+
+// normalizeHex checks that s is hex of size bytes and returns it
+// lower case with a 0x prefix.
+func normalizeHex(s string, size int) (string, error) {
+	b, err := bls.DecodeHex([]byte(s), size)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidInterchange, err)
+	}
+	return string(bls.EncodeHex(b)), nil
+}
+
+func pubkeyKey(pub *g1pubs.PublicKey) string {
+	b := pub.Serialize()
+	return string(bls.EncodeHex(b[:]))
+}
+
+// Open opens the store at path for the chain with the given genesis
+// validators root, creating it on the first signature if it does not
+// exist.
+func Open(path string, genesisValidatorsRoot [32]byte) (*Store, error) {
+	s := &Store{
+		path:                  path,
+		genesisValidatorsRoot: encodeRoot(genesisValidatorsRoot),
+		history:               make(map[string]*InterchangeData),
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := s.importLocked(f); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) historyFor(pubkey string) *InterchangeData {
+	h, ok := s.history[pubkey]
+	if !ok {
+		h = &InterchangeData{Pubkey: pubkey}
+		s.history[pubkey] = h
+	}
+	return h
+}
+
+// mergeRoots returns the signing root of two records of the same
+// message, or no root if they differ.
+func mergeRoots(a string, b string) string {
+	if a == b {
+		return a
+	}
+	return ""
+}
+
+// minify replaces the history with the highest signed block and a single
+// attestation at the highest signed source and target epochs, as in the
+// minimal strategy of EIP-3076. Refusing everything at or below these
+// watermarks keeps the history bounded while preventing slashing.
+func minify(h *InterchangeData) {
+	if len(h.SignedBlocks) > 1 {
+		top := h.SignedBlocks[0]
+		for _, b := range h.SignedBlocks[1:] {
+			if b.Slot > top.Slot {
+				top = b
+			} else if b.Slot == top.Slot {
+				top.SigningRoot = mergeRoots(top.SigningRoot, b.SigningRoot)
+			}
+		}
+		h.SignedBlocks = []SignedBlock{top}
+	}
+	if len(h.SignedAttestations) > 1 {
+		var source, target uint64
+		for _, a := range h.SignedAttestations {
+			if a.SourceEpoch > source {
+				source = a.SourceEpoch
+			}
+			if a.TargetEpoch > target {
+				target = a.TargetEpoch
+			}
+		}
+		top := SignedAttestation{SourceEpoch: source, TargetEpoch: target}
+		found := false
+		for _, a := range h.SignedAttestations {
+			if a.SourceEpoch == source && a.TargetEpoch == target {
+				if found {
+					top.SigningRoot = mergeRoots(top.SigningRoot, a.SigningRoot)
+				} else {
+					top.SigningRoot = a.SigningRoot
+					found = true
+				}
+			}
+		}
+		h.SignedAttestations = []SignedAttestation{top}
+	}
+}
+
+// checkBlock checks a block against the minified history. It returns
+// true if the block was signed before with the same signing root. Blocks
+// at or below the highest signed slot are refused.
+func checkBlock(h *InterchangeData, slot uint64, root string) (bool, error) {
+	if len(h.SignedBlocks) == 0 {
+		return false, nil
+	}
+	top := h.SignedBlocks[0]
+	if top.Slot == slot && top.SigningRoot != "" && top.SigningRoot == root {
+		return true, nil
+	}
+	if slot <= top.Slot {
+		return false, fmt.Errorf("%w: slot %d is not after the highest signed slot %d", ErrSlashableBlock, slot, top.Slot)
+	}
+	return false, nil
+}
+
+// checkAttestation checks an attestation against the minified history.
+// It returns true if the attestation was signed before with the same
+// signing root. Attestations below the highest signed source or at or
+// below the highest signed target are refused, which rules out double
+// and surround votes.
+func checkAttestation(h *InterchangeData, source uint64, target uint64, root string) (bool, error) {
+	if source > target {
+		return false, fmt.Errorf("%w: source epoch %d is after target epoch %d", ErrSlashableAttestation, source, target)
+	}
+	if len(h.SignedAttestations) == 0 {
+		return false, nil
+	}
+	top := h.SignedAttestations[0]
+	if top.SourceEpoch == source && top.TargetEpoch == target && top.SigningRoot != "" && top.SigningRoot == root {
+		return true, nil
+	}
+	if source < top.SourceEpoch || target <= top.TargetEpoch {
+		return false, fmt.Errorf("%w: %d => %d is not after the highest signed epochs %d => %d", ErrSlashableAttestation, source, target, top.SourceEpoch, top.TargetEpoch)
+	}
+	return false, nil
+}
+
+// CheckAndRecordBlock records that the public key signs a block at slot,
+// or returns ErrSlashableBlock if that could be slashable. Signing the
+// same signing root at the same slot again is allowed.
+func (s *Store) CheckAndRecordBlock(pub *g1pubs.PublicKey, slot uint64, signingRoot [32]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	h := s.historyFor(pubkeyKey(pub))
+	root := encodeRoot(signingRoot)
+	repeat, err := checkBlock(h, slot, root)
+	if err != nil || repeat {
+		return err
+	}
+	h.SignedBlocks = []SignedBlock{{Slot: slot, SigningRoot: root}}
+	return s.saveLocked()
+}
+
+// CheckAndRecordAttestation records that the public key signs an
+// attestation from source to target epoch, or returns
+// ErrSlashableAttestation if that could be slashable. Signing the same
+// signing root for the same epochs again is allowed.
+func (s *Store) CheckAndRecordAttestation(pub *g1pubs.PublicKey, sourceEpoch uint64, targetEpoch uint64, signingRoot [32]byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	h := s.historyFor(pubkeyKey(pub))
+	root := encodeRoot(signingRoot)
+	repeat, err := checkAttestation(h, sourceEpoch, targetEpoch, root)
+	if err != nil || repeat {
+		return err
+	}
+	h.SignedAttestations = []SignedAttestation{{
+		SourceEpoch: sourceEpoch,
+		TargetEpoch: targetEpoch,
+		SigningRoot: root,
+	}}
+	return s.saveLocked()
+}
+
+// ethDST is the tag of the ciphersuite of Ethereum validator signatures.
+var ethDST = []byte(g1pubs.CiphersuitePOP)
+
+// SignBlock signs the signing root of a block with the ciphersuite of
+// Ethereum validator signatures after recording it in the store.
+func (s *Store) SignBlock(key *g1pubs.SecretKey, slot uint64, signingRoot [32]byte) (*g1pubs.Signature, error) {
+	if err := s.CheckAndRecordBlock(g1pubs.PrivToPub(key), slot, signingRoot); err != nil {
+		return nil, err
+	}
+	return g1pubs.SignWithDST(signingRoot[:], key, ethDST)
+}
+
+// SignAttestation signs the signing root of an attestation with the
+// ciphersuite of Ethereum validator signatures after recording it in
+// the store.
+func (s *Store) SignAttestation(key *g1pubs.SecretKey, sourceEpoch uint64, targetEpoch uint64, signingRoot [32]byte) (*g1pubs.Signature, error) {
+	if err := s.CheckAndRecordAttestation(g1pubs.PrivToPub(key), sourceEpoch, targetEpoch, signingRoot); err != nil {
+		return nil, err
+	}
+	return g1pubs.SignWithDST(signingRoot[:], key, ethDST)
+}
+
+// Import merges EIP-3076 interchange data into the store. The data must
+// be for the same chain as the store.
+func (s *Store) Import(r io.Reader) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.importLocked(r); err != nil {
+		return err
+	}
+	return s.saveLocked()
+}
+
+func (s *Store) importLocked(r io.Reader) error {
+	var in Interchange
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInterchange, err)
+	}
+	if in.Metadata.InterchangeFormatVersion != InterchangeFormatVersion {
+		return fmt.Errorf("%w: unsupported version %q", ErrInvalidInterchange, in.Metadata.InterchangeFormatVersion)
+	}
+	root, err := normalizeHex(in.Metadata.GenesisValidatorsRoot, 32)
+	if err != nil {
+		return err
+	}
+	if root != s.genesisValidatorsRoot {
+		return ErrGenesisValidatorsRootMismatch
+	}
+
+	// validate everything before changing the store
+	for i := range in.Data {
+		d := &in.Data[i]
+		var pub g1pubs.PublicKey
+		if err := pub.UnmarshalText([]byte(d.Pubkey)); err != nil {
+			return fmt.Errorf("%w: pubkey: %v", ErrInvalidInterchange, err)
+		}
+		d.Pubkey = pubkeyKey(&pub)
+		for j := range d.SignedBlocks {
+			if err := normalizeRoot(&d.SignedBlocks[j].SigningRoot); err != nil {
+				return err
+			}
+		}
+		for j := range d.SignedAttestations {
+			a := &d.SignedAttestations[j]
+			if a.SourceEpoch > a.TargetEpoch {
+				return fmt.Errorf("%w: source epoch %d is after target epoch %d", ErrInvalidInterchange, a.SourceEpoch, a.TargetEpoch)
+			}
+			if err := normalizeRoot(&a.SigningRoot); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, d := range in.Data {
+		h := s.historyFor(d.Pubkey)
+		h.SignedBlocks = append(h.SignedBlocks, d.SignedBlocks...)
+		h.SignedAttestations = append(h.SignedAttestations, d.SignedAttestations...)
+		minify(h)
+	}
+	return nil
+}
+
+func normalizeRoot(root *string) error {
+	if *root == "" {
+		return nil
+	}
+	r, err := normalizeHex(*root, 32)
+	if err != nil {
+		return err
+	}
+	*root = r
+	return nil
+}
+
+func (s *Store) interchangeLocked() *Interchange {
+	out := &Interchange{
+		Metadata: InterchangeMetadata{
+			InterchangeFormatVersion: InterchangeFormatVersion,
+			GenesisValidatorsRoot:    s.genesisValidatorsRoot,
+		},
+		Data: make([]InterchangeData, 0, len(s.history)),
+	}
+	for _, h := range s.history {
+		d := InterchangeData{
+			Pubkey:             h.Pubkey,
+			SignedBlocks:       append([]SignedBlock{}, h.SignedBlocks...),
+			SignedAttestations: append([]SignedAttestation{}, h.SignedAttestations...),
+		}
+		out.Data = append(out.Data, d)
+	}
+	sort.Slice(out.Data, func(i, j int) bool {
+		return strings.Compare(out.Data[i].Pubkey, out.Data[j].Pubkey) < 0
+	})
+	return out
+}
+
+// Export writes the whole store as EIP-3076 interchange data.
+func (s *Store) Export(w io.Writer) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s.interchangeLocked())
+}
+
+// saveLocked atomically replaces the store file.
+func (s *Store) saveLocked() error {
+	data, err := json.MarshalIndent(s.interchangeLocked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(s.path))
+}
+
+// syncDir flushes a directory so that a rename in it is durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := d.Sync(); err != nil {
+		d.Close()
+		return err
+	}
+	return d.Close()
+}
@@ -0,0 +1,337 @@
+package slashing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/phoreproject/bls/g1pubs"
+)
+
+var testGenesisValidatorsRoot = [32]byte{0x04, 0x70, 0x0e}
+
+func tempStore(t *testing.T) (*Store, string) {
+	dir, err := ioutil.TempDir("", "slashing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "slashing-protection.json")
+	s, err := Open(path, testGenesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, path
+}
+
+func removeStore(path string) {
+	os.RemoveAll(filepath.Dir(path))
+}
+
+func root(b byte) [32]byte {
+	return [32]byte{b}
+}
+
+func TestBlockProtection(t *testing.T) {
+	s, path := tempStore(t)
+	defer removeStore(path)
+	key, _ := g1pubs.RandKey(rand.Reader)
+	pub := g1pubs.PrivToPub(key)
+
+	sig, err := s.SignBlock(key, 10, root(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := root(1)
+	if err := g1pubs.VerifyWithDST(r[:], pub, sig, []byte(g1pubs.CiphersuitePOP)); err != nil {
+		t.Fatalf("signature from the store should verify: %v", err)
+	}
+
+	if err := s.CheckAndRecordBlock(pub, 10, root(1)); err != nil {
+		t.Fatal("signing the same block again should be allowed")
+	}
+	if err := s.CheckAndRecordBlock(pub, 10, root(2)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatalf("expected ErrSlashableBlock for a double proposal, got %v", err)
+	}
+	if err := s.CheckAndRecordBlock(pub, 9, root(3)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatalf("expected ErrSlashableBlock below the highest slot, got %v", err)
+	}
+	if err := s.CheckAndRecordBlock(pub, 12, root(4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckAndRecordBlock(pub, 11, root(5)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatalf("expected ErrSlashableBlock below the highest slot, got %v", err)
+	}
+	if err := s.CheckAndRecordBlock(pub, 10, root(1)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatalf("expected ErrSlashableBlock for a block below the watermark, got %v", err)
+	}
+
+	other, _ := g1pubs.RandKey(rand.Reader)
+	if _, err := s.SignBlock(other, 10, root(2)); err != nil {
+		t.Fatal("protection should be per public key")
+	}
+}
+
+func TestAttestationProtection(t *testing.T) {
+	s, path := tempStore(t)
+	defer removeStore(path)
+	key, _ := g1pubs.RandKey(rand.Reader)
+	pub := g1pubs.PrivToPub(key)
+
+	if _, err := s.SignAttestation(key, 2, 5, root(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckAndRecordAttestation(pub, 2, 5, root(1)); err != nil {
+		t.Fatal("signing the same attestation again should be allowed")
+	}
+
+	tests := []struct {
+		source, target uint64
+		reason         string
+	}{
+		{2, 5, "double vote"},
+		{3, 5, "double vote with a different source"},
+		{1, 6, "surrounding vote"},
+		{3, 4, "surrounded vote"},
+		{1, 7, "source below the highest source"},
+		{6, 5, "source after target"},
+	}
+	for _, tt := range tests {
+		if err := s.CheckAndRecordAttestation(pub, tt.source, tt.target, root(2)); !errors.Is(err, ErrSlashableAttestation) {
+			t.Fatalf("expected ErrSlashableAttestation for %s, got %v", tt.reason, err)
+		}
+	}
+
+	if err := s.CheckAndRecordAttestation(pub, 5, 6, root(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckAndRecordAttestation(pub, 3, 7, root(4)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatalf("expected ErrSlashableAttestation for surrounding 5 => 6, got %v", err)
+	}
+	if err := s.CheckAndRecordAttestation(pub, 2, 5, root(1)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatalf("expected ErrSlashableAttestation for an attestation below the watermark, got %v", err)
+	}
+}
+
+func TestStorePersists(t *testing.T) {
+	s, path := tempStore(t)
+	defer removeStore(path)
+	key, _ := g1pubs.RandKey(rand.Reader)
+	pub := g1pubs.PrivToPub(key)
+	if _, err := s.SignBlock(key, 10, root(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignAttestation(key, 2, 5, root(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path, testGenesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.CheckAndRecordBlock(pub, 10, root(2)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatalf("expected the block history to persist, got %v", err)
+	}
+	if err := reopened.CheckAndRecordAttestation(pub, 2, 5, root(2)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatalf("expected the attestation history to persist, got %v", err)
+	}
+
+	if _, err := Open(path, root(9)); !errors.Is(err, ErrGenesisValidatorsRootMismatch) {
+		t.Fatalf("expected ErrGenesisValidatorsRootMismatch, got %v", err)
+	}
+}
+
+func TestInterchangeImportExport(t *testing.T) {
+	key, _ := g1pubs.RandKey(rand.Reader)
+	pub := g1pubs.PrivToPub(key)
+	pubBytes := pub.Serialize()
+	pubHex := "0x" + hex.EncodeToString(pubBytes[:])
+
+	interchange := `{
+		"metadata": {
+			"interchange_format_version": "5",
+			"genesis_validators_root": "0x04700E0000000000000000000000000000000000000000000000000000000000"
+		},
+		"data": [{
+			"pubkey": "` + strings.ToUpper(pubHex[2:]) + `",
+			"signed_blocks": [
+				{"slot": "81952", "signing_root": "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b"},
+				{"slot": "81951"}
+			],
+			"signed_attestations": [
+				{"source_epoch": "2290", "target_epoch": "3007", "signing_root": "0x587d6a4f59a58fe24f406e0502413e77fe1babddee641fda30034ed37ecc884d"},
+				{"source_epoch": "2290", "target_epoch": "3008"}
+			]
+		}]
+	}`
+
+	s, path := tempStore(t)
+	defer removeStore(path)
+	if err := s.Import(strings.NewReader(interchange)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckAndRecordBlock(pub, 81951, root(1)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatal("a block without a signing root should never be signed again")
+	}
+	if err := s.CheckAndRecordBlock(pub, 81900, root(1)); !errors.Is(err, ErrSlashableBlock) {
+		t.Fatal("imported blocks should set the highest slot")
+	}
+	if err := s.CheckAndRecordAttestation(pub, 2290, 3008, root(1)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatal("an attestation without a signing root should never be signed again")
+	}
+	if err := s.CheckAndRecordAttestation(pub, 2291, 3009, root(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// importing the same data twice should not duplicate it
+	if err := s.Import(strings.NewReader(interchange)); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Export(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var out Interchange
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Metadata.InterchangeFormatVersion != "5" || out.Metadata.GenesisValidatorsRoot != "0x04700e0000000000000000000000000000000000000000000000000000000000" {
+		t.Fatalf("unexpected metadata %+v", out.Metadata)
+	}
+	if len(out.Data) != 1 || out.Data[0].Pubkey != pubHex {
+		t.Fatal("expected one normalized public key")
+	}
+	blocks := out.Data[0].SignedBlocks
+	if len(blocks) != 1 || blocks[0].Slot != 81952 || blocks[0].SigningRoot != "0x4ff6f743a43f3b4f95350831aeaf0a122a1a392922c45d804280284a69eb850b" {
+		t.Fatalf("expected only the highest block, got %+v", blocks)
+	}
+	attestations := out.Data[0].SignedAttestations
+	if len(attestations) != 1 || attestations[0].SourceEpoch != 2291 || attestations[0].TargetEpoch != 3009 {
+		t.Fatalf("expected only the highest attestation, got %+v", attestations)
+	}
+	if !strings.Contains(buf.String(), `"slot": "81952"`) {
+		t.Fatal("slots should be exported as decimal strings")
+	}
+
+	s2, path2 := tempStore(t)
+	defer removeStore(path2)
+	if err := s2.Import(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := s2.CheckAndRecordAttestation(pub, 2291, 3009, root(2)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatal("exported history should import into another store")
+	}
+}
+
+func TestHistoryIsMinified(t *testing.T) {
+	s, path := tempStore(t)
+	defer removeStore(path)
+	key, _ := g1pubs.RandKey(rand.Reader)
+	pub := g1pubs.PrivToPub(key)
+	pubBytes := pub.Serialize()
+
+	// the highest source and target come from different attestations
+	interchange := `{
+		"metadata": {
+			"interchange_format_version": "5",
+			"genesis_validators_root": "0x04700e0000000000000000000000000000000000000000000000000000000000"
+		},
+		"data": [{
+			"pubkey": "0x` + hex.EncodeToString(pubBytes[:]) + `",
+			"signed_blocks": [],
+			"signed_attestations": [
+				{"source_epoch": "10", "target_epoch": "20"},
+				{"source_epoch": "15", "target_epoch": "18"}
+			]
+		}]
+	}`
+	if err := s.Import(strings.NewReader(interchange)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckAndRecordAttestation(pub, 14, 21, root(1)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatalf("expected ErrSlashableAttestation below the highest source, got %v", err)
+	}
+	if err := s.CheckAndRecordAttestation(pub, 15, 20, root(1)); !errors.Is(err, ErrSlashableAttestation) {
+		t.Fatalf("expected ErrSlashableAttestation at the highest target, got %v", err)
+	}
+	if err := s.CheckAndRecordAttestation(pub, 15, 21, root(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := uint64(1); i <= 100; i++ {
+		if err := s.CheckAndRecordBlock(pub, i, root(byte(i))); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.CheckAndRecordAttestation(pub, 20+i, 21+i, root(byte(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reopened, err := Open(path, testGenesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := reopened.history[pubkeyKey(pub)]
+	if len(h.SignedBlocks) != 1 || h.SignedBlocks[0].Slot != 100 {
+		t.Fatalf("expected only the highest block to be stored, got %+v", h.SignedBlocks)
+	}
+	if len(h.SignedAttestations) != 1 || h.SignedAttestations[0].TargetEpoch != 121 {
+		t.Fatalf("expected only the highest attestation to be stored, got %+v", h.SignedAttestations)
+	}
+}
+
+func TestImportInvalid(t *testing.T) {
+	s, path := tempStore(t)
+	defer removeStore(path)
+	gvr := `"genesis_validators_root": "0x04700e0000000000000000000000000000000000000000000000000000000000"`
+	tests := []struct {
+		data string
+		err  error
+	}{
+		{`{`, ErrInvalidInterchange},
+		{`{"metadata": {"interchange_format_version": "4", ` + gvr + `}, "data": []}`, ErrInvalidInterchange},
+		{`{"metadata": {"interchange_format_version": "5", "genesis_validators_root": "0x01"}, "data": []}`, ErrInvalidInterchange},
+		{`{"metadata": {"interchange_format_version": "5", "genesis_validators_root": "0x0000000000000000000000000000000000000000000000000000000000000000"}, "data": []}`, ErrGenesisValidatorsRootMismatch},
+		{`{"metadata": {"interchange_format_version": "5", ` + gvr + `}, "data": [{"pubkey": "0x00", "signed_blocks": [], "signed_attestations": []}]}`, ErrInvalidInterchange},
+	}
+	for i, tt := range tests {
+		if err := s.Import(strings.NewReader(tt.data)); !errors.Is(err, tt.err) {
+			t.Fatalf("case %d: expected %v, got %v", i, tt.err, err)
+		}
+	}
+}
+
+func TestConcurrentSigning(t *testing.T) {
+	s, path := tempStore(t)
+	defer removeStore(path)
+	key, _ := g1pubs.RandKey(rand.Reader)
+	pub := g1pubs.PrivToPub(key)
+
+	var wg sync.WaitGroup
+	results := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results <- s.CheckAndRecordBlock(pub, 100, root(byte(i)))
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+	signed := 0
+	for err := range results {
+		if err == nil {
+			signed++
+		}
+	}
+	if signed != 1 {
+		t.Fatalf("expected exactly one conflicting block to be signed, got %d", signed)
+	}
+}
@@ -1,5 +1,5 @@
-// +build amd64
-// +build gc
+//go:build amd64 && gc && !purego
+// +build amd64,gc,!purego
 
 package bls
 
@@ -31,3 +31,17 @@ func SubWithBorrow(a, b, borrow uint64) (uint64, uint64)
 // MACWithCarry finds the value a + b * c + carry and returns the
 // full 128-bit value in 2 64-bit integers.
 func MACWithCarry(a, b, c, carry uint64) (uint64, uint64)
+
+// MultiplyFR multiplies two FR values in Montgomery form and returns
+// the fully reduced product in Montgomery form.
+func MultiplyFR(a, b [4]uint64) [4]uint64
+
+// SquareFR squares an FR value in Montgomery form and returns the
+// fully reduced result in Montgomery form.
+func SquareFR(a [4]uint64) [4]uint64
+
+// AddFR finds the value of a + b mod r for two reduced FR values.
+func AddFR(a, b [4]uint64) [4]uint64
+
+// SubFR finds the value of a - b mod r for two reduced FR values.
+func SubFR(a, b [4]uint64) [4]uint64
@@ -0,0 +1,182 @@
+package bls
+
+// GTCompressedT2Size is the size of a GT element compressed
+// with T2 torus compression.
+const GTCompressedT2Size = 288
+
+// GTCompressedT6Size is the size of a GT element compressed
+// with T6 torus compression.
+const GTCompressedT6Size = 192
+
+// torusIdentityFlag marks the compressed encoding of the identity,
+// which has no affine torus representation.
+const torusIdentityFlag = 0x40
+
+var fq6V = NewFQ6(FQ2Zero, FQ2One, FQ2Zero)
+
+var oneThirdFQ2 = func() FQ2 {
+	f := NewFQ2(bigThreeFQ, FQZero)
+	f.InverseAssign()
+	return f
+}()
+
+// TorusCompressT2 compresses an element of the cyclotomic subgroup
+// c0 + c1*w to t = (1 + c0) / c1. Returns false if c1 is zero, which
+// only happens for 1 and -1.
+func (f *FQ12) TorusCompressT2() (*FQ6, bool) {
+	t := f.c1.Copy()
+	if !t.InverseAssign() {
+		return nil, false
+	}
+	num := f.c0.Copy()
+	num.AddAssign(FQ6One)
+	t.MulAssign(num)
+	return t, true
+}
+
+// TorusDecompressT2 decompresses t to (t + w) / (t - w).
+func TorusDecompressT2(t *FQ6) *FQ12 {
+	// (t + w) / (t - w) = (t^2 + v + 2t*w) / (t^2 - v)
+	t2 := t.Copy()
+	t2.SquareAssign()
+
+	// v is not a square in FQ6, so the denominator is never zero.
+	den := t2.Copy()
+	den.SubAssign(fq6V)
+	den.InverseAssign()
+
+	c0 := t2
+	c0.AddAssign(fq6V)
+	c0.MulAssign(den)
+
+	c1 := t.Copy()
+	c1.DoubleAssign()
+	c1.MulAssign(den)
+
+	return NewFQ12(c0, c1)
+}
+
+// TorusCompressT6 compresses an element of T6(FQ2) to the v and v^2
+// coefficients of its T2 representation. Returns false for 1, and for
+// elements whose v coefficient is zero since TorusDecompressT6 cannot
+// recover them.
+func (f *FQ12) TorusCompressT6() (FQ2, FQ2, bool) {
+	t, ok := f.TorusCompressT2()
+	if !ok || t.c1.IsZero() {
+		return FQ2{}, FQ2{}, false
+	}
+	return t.c1, t.c2, true
+}
+
+// TorusDecompressT6 decompresses the v and v^2 coefficients of the T2
+// representation. Elements of T6(FQ2) satisfy a*b - xi*c^2 = 1/3 where
+// t = a + b*v + c*v^2 and xi = 1 + u, so a can be recovered from b and c.
+func TorusDecompressT6(b FQ2, c FQ2) (*FQ12, bool) {
+	bInv := b.Copy()
+	if !bInv.InverseAssign() {
+		return nil, false
+	}
+	a := c.Copy()
+	a.SquareAssign()
+	a.MultiplyByNonresidueAssign()
+	a.AddAssign(oneThirdFQ2)
+	a.MulAssign(bInv)
+	return TorusDecompressT2(NewFQ6(a, b, c)), true
+}
+
+func isTorusIdentity(b []byte) (bool, error) {
+	if b[0]&torusIdentityFlag == 0 {
+		return false, nil
+	}
+	if b[0] != torusIdentityFlag {
+		return false, ErrInfinityNotZero
+	}
+	for _, v := range b[1:] {
+		if v != 0 {
+			return false, ErrInfinityNotZero
+		}
+	}
+	return true, nil
+}
+
+// CompressT2 compresses the GT element to 288 bytes.
+func (g *GT) CompressT2() [GTCompressedT2Size]byte {
+	var out [GTCompressedT2Size]byte
+	t, ok := g.f.TorusCompressT2()
+	if !ok {
+		out[0] = torusIdentityFlag
+		return out
+	}
+	putFQ6(out[:], t)
+	return out
+}
+
+// DecompressGTT2 decompresses a T2 compressed GT element and checks
+// that it is in the correct subgroup.
+func DecompressGTT2(b [GTCompressedT2Size]byte) (*GT, error) {
+	identity, err := isTorusIdentity(b[:])
+	if err != nil {
+		return nil, err
+	}
+	if identity {
+		return GTOne.Copy(), nil
+	}
+	t, err := getFQ6(b[:])
+	if err != nil {
+		return nil, err
+	}
+	g := &GT{f: TorusDecompressT2(t)}
+	if !g.IsInCorrectSubgroup() {
+		return nil, ErrNotInSubgroup
+	}
+	return g, nil
+}
+
+// CompressT6 compresses the GT element to 192 bytes. It returns
+// ErrNotTorusCompressible for the elements TorusCompressT6 rejects,
+// other than the identity.
+func (g *GT) CompressT6() ([GTCompressedT6Size]byte, error) {
+	var out [GTCompressedT6Size]byte
+	if g.IsOne() {
+		out[0] = torusIdentityFlag
+		return out, nil
+	}
+	b, c, ok := g.f.TorusCompressT6()
+	if !ok {
+		return out, ErrNotTorusCompressible
+	}
+	putFQ(out[0:], b.c0)
+	putFQ(out[48:], b.c1)
+	putFQ(out[96:], c.c0)
+	putFQ(out[144:], c.c1)
+	return out, nil
+}
+
+// DecompressGTT6 decompresses a T6 compressed GT element and checks
+// that it is in the correct subgroup.
+func DecompressGTT6(in [GTCompressedT6Size]byte) (*GT, error) {
+	identity, err := isTorusIdentity(in[:])
+	if err != nil {
+		return nil, err
+	}
+	if identity {
+		return GTOne.Copy(), nil
+	}
+	var c [4]FQ
+	for i := range c {
+		f, err := getFQ(in[48*i : 48*(i+1)])
+		if err != nil {
+			return nil, err
+		}
+		c[i] = f
+	}
+	f, ok := TorusDecompressT6(NewFQ2(c[0], c[1]), NewFQ2(c[2], c[3]))
+	if !ok {
+		return nil, ErrInvalidEncoding
+	}
+	g := &GT{f: f}
+	if !g.IsInCorrectSubgroup() {
+		return nil, ErrNotInSubgroup
+	}
+	return g, nil
+}
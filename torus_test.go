@@ -0,0 +1,132 @@
+package bls_test
+
+import (
+	"testing"
+
+	"github.com/phoreproject/bls"
+)
+
+func TestTorusCompressionRoundTrip(t *testing.T) {
+	r := NewXORShift(1)
+	for i := 0; i < 5; i++ {
+		p, _ := bls.RandG1(r)
+		q, _ := bls.RandG2(r)
+		e := bls.PairingGT(p, q)
+
+		t2 := e.CompressT2()
+		fromT2, err := bls.DecompressGTT2(t2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fromT2.Equal(e) {
+			t.Fatal("T2 compression did not round trip")
+		}
+
+		t6, err := e.CompressT6()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromT6, err := bls.DecompressGTT6(t6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fromT6.Equal(e) {
+			t.Fatal("T6 compression did not round trip")
+		}
+	}
+}
+
+func TestTorusCompressionIdentity(t *testing.T) {
+	t2 := bls.GTOne.CompressT2()
+	fromT2, err := bls.DecompressGTT2(t2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromT2.IsOne() {
+		t.Fatal("identity did not round trip through T2 compression")
+	}
+
+	t6, err := bls.GTOne.CompressT6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromT6, err := bls.DecompressGTT6(t6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fromT6.IsOne() {
+		t.Fatal("identity did not round trip through T6 compression")
+	}
+
+	t6[len(t6)-1] = 1
+	if _, err := bls.DecompressGTT6(t6); err == nil {
+		t.Fatal("expected error for identity with trailing data")
+	}
+}
+
+func TestTorusCompressionInvalid(t *testing.T) {
+	var zero [bls.GTCompressedT6Size]byte
+	if _, err := bls.DecompressGTT6(zero); err == nil {
+		t.Fatal("expected error for T6 element with zero v coefficient")
+	}
+
+	// an arbitrary T2 value lies on the torus but almost certainly not in GT
+	var t2 [bls.GTCompressedT2Size]byte
+	t2[47] = 5
+	if _, err := bls.DecompressGTT2(t2); err == nil {
+		t.Fatal("expected error for T2 element outside GT")
+	}
+
+	var t6 [bls.GTCompressedT6Size]byte
+	t6[47] = 5
+	if _, err := bls.DecompressGTT6(t6); err == nil {
+		t.Fatal("expected error for T6 element outside GT")
+	}
+}
+
+func TestTorusDecompressT6IsCyclotomic(t *testing.T) {
+	r := NewXORShift(2)
+	for i := 0; i < 5; i++ {
+		b, _ := bls.RandFQ2(r)
+		c, _ := bls.RandFQ2(r)
+		f, ok := bls.TorusDecompressT6(b, c)
+		if !ok {
+			t.Fatal("random coefficients should decompress")
+		}
+
+		// T6(FQ2) is the cyclotomic subgroup: f^(q^4 - q^2 + 1) = 1
+		lhs := f.Copy()
+		lhs.FrobeniusMapAssign(4)
+		lhs.MulAssign(f)
+		rhs := f.Copy()
+		rhs.FrobeniusMapAssign(2)
+		if !lhs.Equals(rhs) {
+			t.Fatal("T6 decompression should land in the cyclotomic subgroup")
+		}
+
+		gotB, gotC, ok := f.TorusCompressT6()
+		if !ok || !gotB.Equals(b) || !gotC.Equals(c) {
+			t.Fatal("T6 compression did not invert decompression")
+		}
+	}
+}
+
+func TestTorusCompressT6ZeroV(t *testing.T) {
+	r := NewXORShift(3)
+	a, _ := bls.RandFQ2(r)
+	c, _ := bls.RandFQ2(r)
+
+	// T6 compression drops the constant coefficient of t, which cannot
+	// be recovered when the v coefficient is zero, so compression must
+	// fail rather than produce an encoding that does not decode.
+	f := bls.TorusDecompressT2(bls.NewFQ6(a, bls.FQ2Zero, c))
+	if _, ok := f.TorusCompressT2(); !ok {
+		t.Fatal("element should have a T2 representation")
+	}
+	if _, _, ok := f.TorusCompressT6(); ok {
+		t.Fatal("expected T6 compression to fail for a zero v coefficient")
+	}
+	if _, ok := bls.TorusDecompressT6(bls.FQ2Zero, c); ok {
+		t.Fatal("expected T6 decompression to fail for a zero v coefficient")
+	}
+}